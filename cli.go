@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/cj3636/gotui/internal/app"
+	"github.com/cj3636/gotui/internal/config"
+	"github.com/cj3636/gotui/internal/httpclient"
+	"github.com/cj3636/gotui/internal/logging"
+	"github.com/cj3636/gotui/widgets"
+)
+
+const usage = `Usage:
+  gotui [run] [flags]              Start the dashboard
+  gotui render [flags]             Print one frame of the dashboard and exit
+  gotui config validate [file]     Check a config file and report problems
+  gotui config init [--force] [file]
+                                   Write a commented starter config
+                                   (default ~/.config/gotui/config.yaml)
+  gotui widgets list               Describe the widget types and their options
+
+Flags for run:
+  --config PATH     Config file (default ./config.yaml, then ~/.config/gotui/config.yaml)
+  --page NAME       Page to show first, by name or number
+  --no-alt-screen   Draw in the normal terminal buffer instead of the alternate screen
+  --log-file PATH   Write log output to PATH
+  --log-level LEVEL Least severe level written to the log file: debug, info,
+                    warn or error (default info)
+  --version         Print the version and exit
+
+Flags for render:
+  --config PATH     Config file, as for run
+  --page NAME       Page to render, by name or number
+  --width N         Frame width in columns (default 120)
+  --height N        Frame height in lines (default 40)
+  --timeout D       How long to wait for widgets to load (default 10s)
+  --plain           Write plain text instead of ANSI colors
+`
+
+// Exit statuses
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// run dispatches the command line to a command and returns the exit status
+func run(args []string) int {
+	httpclient.SetUserAgent("gotui/" + versionString())
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runDashboard(args)
+	}
+
+	switch args[0] {
+	case "run":
+		return runDashboard(args[1:])
+	case "render":
+		return renderDashboard(args[1:])
+	case "config":
+		if len(args) > 1 {
+			switch args[1] {
+			case "validate":
+				return validateConfig(args[2:])
+			case "init":
+				return initConfig(args[2:])
+			}
+		}
+	case "widgets":
+		if len(args) > 1 && args[1] == "list" {
+			return listWidgets(os.Stdout)
+		}
+	case "help":
+		fmt.Print(usage)
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "gotui: unknown command %q\n\n%s", strings.Join(args, " "), usage)
+	return exitUsage
+}
+
+// newFlagSet returns a flag set that prints the shared usage on error
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	return fs
+}
+
+// parseFlags parses args, returning the exit status to stop with when
+// parsing fails or help was asked for
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// versionString returns the version set at build time, falling back to the
+// module version when installed with go install
+func versionString() string {
+	if version == "dev" {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			return info.Main.Version
+		}
+	}
+	return version
+}
+
+func runDashboard(args []string) int {
+	fs := newFlagSet("run")
+	configPath := fs.String("config", "", "config file")
+	page := fs.String("page", "", "page to show first")
+	noAltScreen := fs.Bool("no-alt-screen", false, "don't use the alternate screen")
+	logFile := fs.String("log-file", "", "log file")
+	logLevel := fs.String("log-level", "info", "log file level")
+	showVersion := fs.Bool("version", false, "print the version")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "gotui: unexpected argument %q\n\n%s", fs.Arg(0), usage)
+		return exitUsage
+	}
+
+	if *showVersion {
+		fmt.Printf("gotui %s\n", versionString())
+		return exitOK
+	}
+
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: %v\n\n%s", err, usage)
+		return exitUsage
+	}
+	if *logFile != "" {
+		f, err := logging.OpenFile(*logFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		logging.Setup(f, level)
+	} else {
+		// Nothing may be written to the terminal while the dashboard is up
+		logging.Setup(nil, level)
+	}
+	slog.Info("starting", "version", versionString())
+
+	cfg, err := app.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: failed to load config: %v\n", err)
+		return exitError
+	}
+
+	model, err := app.NewModel(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: failed to build dashboard: %v\n", err)
+		return exitError
+	}
+	if *page != "" {
+		if err := model.ShowPage(*page); err != nil {
+			fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+			return exitError
+		}
+	}
+
+	opts := []tea.ProgramOption{tea.WithMouseCellMotion()}
+	if !*noAltScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	if _, err := tea.NewProgram(model, opts...).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// ansiSequence matches terminal escape sequences, for --plain output of
+// text styled outside lipgloss
+var ansiSequence = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\))`)
+
+// renderDashboard prints a single frame of the dashboard once its widgets
+// have loaded. Widgets that are still loading when the timeout passes are
+// rendered as they are and reported, and the command fails.
+func renderDashboard(args []string) int {
+	fs := newFlagSet("render")
+	configPath := fs.String("config", "", "config file")
+	page := fs.String("page", "", "page to render")
+	width := fs.Int("width", 120, "frame width")
+	height := fs.Int("height", 40, "frame height")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for widgets")
+	plain := fs.Bool("plain", false, "write plain text")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "gotui: unexpected argument %q\n\n%s", fs.Arg(0), usage)
+		return exitUsage
+	}
+	if *width < 1 || *height < 1 {
+		fmt.Fprintln(os.Stderr, "gotui: --width and --height must be at least 1")
+		return exitUsage
+	}
+
+	// Keep log records for the log widget and out of the frame
+	logging.Setup(nil, slog.LevelInfo)
+
+	// Output usually goes to a file or pipe, so pick the colors explicitly
+	// rather than detecting them from stdout
+	if *plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+
+	cfg, err := app.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: failed to load config: %v\n", err)
+		return exitError
+	}
+
+	model, err := app.NewModel(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: failed to build dashboard: %v\n", err)
+		return exitError
+	}
+	if *page != "" {
+		if err := model.ShowPage(*page); err != nil {
+			fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+			return exitError
+		}
+	}
+
+	frame, loading := app.Render(model, *width, *height, *timeout)
+	if *plain {
+		frame = ansiSequence.ReplaceAllString(frame, "")
+	}
+	fmt.Println(frame)
+
+	if len(loading) > 0 {
+		fmt.Fprintf(os.Stderr, "gotui: timed out after %s waiting for %s\n", *timeout, strings.Join(loading, ", "))
+		return exitError
+	}
+	return exitOK
+}
+
+// validateConfig checks a config file, printing every problem as
+// file:line: level: message. It fails when there are errors; warnings alone
+// don't fail it.
+func validateConfig(args []string) int {
+	fs := newFlagSet("config validate")
+	configPath := fs.String("config", "", "config file")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+
+	path := *configPath
+	switch {
+	case fs.NArg() > 1:
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	case fs.NArg() == 1:
+		path = fs.Arg(0)
+	case path == "":
+		var err error
+		if path, err = config.FindConfigFile(); err != nil {
+			fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+			return exitError
+		}
+	}
+
+	problems, err := config.Check(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+		return exitError
+	}
+
+	errs, warnings := 0, 0
+	for _, p := range problems {
+		location := path
+		if p.Line > 0 {
+			location = fmt.Sprintf("%s:%d", path, p.Line)
+		}
+		level := "error"
+		if p.Warning {
+			level = "warning"
+			warnings++
+		} else {
+			errs++
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s: %s\n", location, level, p.Message)
+	}
+
+	if errs > 0 {
+		fmt.Fprintf(os.Stderr, "%s: %d error(s), %d warning(s)\n", path, errs, warnings)
+		return exitError
+	}
+	fmt.Printf("%s is valid (%d warning(s))\n", path, warnings)
+	return exitOK
+}
+
+// initConfig writes the commented starter config, refusing to overwrite an
+// existing file unless forced
+func initConfig(args []string) int {
+	fs := newFlagSet("config init")
+	force := fs.Bool("force", false, "overwrite an existing file")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+
+	var path string
+	switch fs.NArg() {
+	case 0:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+			return exitError
+		}
+		path = filepath.Join(home, ".config", "gotui", "config.yaml")
+	case 1:
+		path = fs.Arg(0)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	}
+
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "gotui: %s already exists (use --force to overwrite)\n", path)
+		return exitError
+	}
+
+	// The file may end up holding API tokens, so keep it private
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+		return exitError
+	}
+	if err := os.WriteFile(path, starterConfig(), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "gotui: %v\n", err)
+		return exitError
+	}
+	fmt.Printf("Wrote %s\n", path)
+	return exitOK
+}
+
+// starterEdits adapt the example config to where "config init" writes it:
+// the header needn't say to copy it, and the example's markdown file sits
+// next to the example, not next to the config
+var starterEdits = []struct{ old, new string }{
+	{
+		"# GoTUI Dashboard Configuration Example\n",
+		"# GoTUI Dashboard Configuration\n",
+	},
+	{
+		"# Copy this file to config.yaml and customize it for your needs\n",
+		"# Written by \"gotui config init\"; customize it for your needs\n",
+	},
+	{
+		"markdown_file: \"example.md\"\n",
+		"markdown_file: \"\"\n",
+	},
+}
+
+// starterConfig returns the config "config init" writes
+func starterConfig() []byte {
+	data := exampleConfig
+	for _, e := range starterEdits {
+		data = bytes.Replace(data, []byte(e.old), []byte(e.new), 1)
+	}
+	return data
+}
+
+// listWidgets describes every registered widget type and its options
+func listWidgets(out io.Writer) int {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, name := range widgets.Types() {
+		t, _ := widgets.Lookup(name)
+		fmt.Fprintf(tw, "%s\t%s\n", t.Name, t.Description)
+		for _, opt := range t.Options {
+			fmt.Fprintf(tw, "  %s\t%s: %s\n", opt.Name, kindName(opt.Kind), opt.Help)
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintln(out, "\nEvery entry also accepts type, id, title, refresh (seconds), stale_after (seconds), row, col, row_span, col_span and theme.")
+	return exitOK
+}
+
+func kindName(kind widgets.OptionKind) string {
+	switch kind {
+	case widgets.IntOption:
+		return "number"
+	case widgets.StringListOption:
+		return "list"
+	}
+	return "string"
+}
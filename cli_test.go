@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cj3636/gotui/internal/config"
+)
+
+// The starter config must load cleanly wherever it is written, so it can't
+// refer to files that sit next to the example
+func TestStarterConfig(t *testing.T) {
+	for _, e := range starterEdits {
+		if !bytes.Contains(exampleConfig, []byte(e.old)) {
+			t.Errorf("config.example.yaml no longer contains %q", e.old)
+		}
+	}
+
+	data := starterConfig()
+	for _, stale := range []string{"example.md", "Copy this file"} {
+		if bytes.Contains(data, []byte(stale)) {
+			t.Errorf("starter config still mentions %q", stale)
+		}
+	}
+
+	// Run from elsewhere, as the written file will be
+	t.Chdir(t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := config.Check(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		if !p.Warning {
+			t.Errorf("starter config: %s", p)
+		}
+	}
+}
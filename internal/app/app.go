@@ -1,114 +1,231 @@
 package app
 
 import (
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cj3636/gotui/internal/config"
-	"github.com/cj3636/gotui/internal/widgets"
+	"github.com/cj3636/gotui/internal/keymap"
+	"github.com/cj3636/gotui/internal/layout"
+	"github.com/cj3636/gotui/internal/theme"
+	"github.com/cj3636/gotui/widgets"
 )
 
 // Model is the main application model
 type Model struct {
-	config  *config.Config
-	widgets []widgets.Widget
-	width   int
-	height  int
-	ready   bool
+	config   *config.Config
+	theme    theme.Theme
+	keys     keymap.KeyMap
+	pages    []page
+	current  int
+	zoomed   bool
+	showHelp bool
+	palette  *palette
+	// paused holds every scheduled refresh until refreshing is resumed
+	paused bool
+	width  int
+	height int
+	ready  bool
+
+	// stamp is the version of the config file last loaded
+	stamp     fileStamp
+	status    string
+	statusErr bool
+	statusAt  time.Time
 }
 
-// NewModel creates a new application model
-func NewModel(cfg *config.Config) Model {
-	// Create widgets based on configuration
-	var widgetList []widgets.Widget
-
-	// Add clock and calendar
-	widgetList = append(widgetList, widgets.NewClockWidget())
-	widgetList = append(widgetList, widgets.NewCalendarWidget())
-
-	// Add weather widget
-	if cfg.WeatherLocation != "" {
-		widgetList = append(widgetList, widgets.NewWeatherWidget(
-			cfg.WeatherLocation,
-			cfg.RefreshIntervals.Weather,
-		))
-	}
-
-	// Add GitHub widget
-	if len(cfg.GithubRepos) > 0 {
-		widgetList = append(widgetList, widgets.NewGithubWidget(
-			cfg.GithubToken,
-			cfg.GithubRepos,
-			cfg.RefreshIntervals.Github,
-		))
-	}
-
-	// Add GitLab widget
-	if len(cfg.GitlabProjects) > 0 {
-		widgetList = append(widgetList, widgets.NewGitlabWidget(
-			cfg.GitlabToken,
-			cfg.GitlabProjects,
-			cfg.RefreshIntervals.Gitlab,
-		))
-	}
-
-	// Add system resources widget
-	widgetList = append(widgetList, widgets.NewSystemWidget(cfg.RefreshIntervals.System))
-
-	// Add IP information widget
-	widgetList = append(widgetList, widgets.NewIPWidget(cfg.RefreshIntervals.IP))
-
-	// Add SMART status widget
-	widgetList = append(widgetList, widgets.NewSMARTWidget())
-
-	// Add text viewer widget
-	if cfg.TextFile != "" {
-		widgetList = append(widgetList, widgets.NewTextViewerWidget(cfg.TextFile))
+// NewModel creates a new application model with the pages and widgets
+// listed in the configuration, in order
+func NewModel(cfg *config.Config) (Model, error) {
+	th := cfg.Theme.Resolve(theme.Default())
+	pages := make([]page, 0, len(cfg.Pages))
+	for _, pc := range cfg.Pages {
+		p, err := newPage(pc, th, nil)
+		if err != nil {
+			return Model{}, err
+		}
+		pages = append(pages, p)
 	}
 
-	// Add markdown viewer widget
-	if cfg.MarkdownFile != "" {
-		widgetList = append(widgetList, widgets.NewMarkdownWidget(cfg.MarkdownFile))
+	m := Model{
+		config: cfg,
+		theme:  th,
+		keys:   cfg.KeyMap(),
+		pages:  pages,
+		stamp:  statFile(cfg.Path),
 	}
-
-	return Model{
-		config:  cfg,
-		widgets: widgetList,
+	logWarnings(cfg.Warnings)
+	if len(cfg.Warnings) > 0 {
+		m.setStatus(warningSummary("Config loaded", cfg.Warnings), false)
 	}
+	return m, nil
 }
 
 // Init initializes the application
 func (m Model) Init() tea.Cmd {
-	var cmds []tea.Cmd
-	for _, widget := range m.widgets {
-		cmds = append(cmds, widget.Init())
+	cmds := []tea.Cmd{m.watchConfig()}
+	for _, p := range m.pages {
+		for _, widget := range p.widgets {
+			cmds = append(cmds, widget.Init())
+		}
 	}
 	return tea.Batch(cmds...)
 }
 
+// page returns the page being shown
+func (m *Model) page() *page {
+	return &m.pages[m.current]
+}
+
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		if m.palette != nil {
+			return m.updatePalette(msg)
+		}
+		if m.showHelp {
+			// The overlay takes every key until it is closed
+			switch {
+			case key.Matches(msg, m.keys.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Help, m.keys.Back):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Back):
+			if m.zoomed {
+				m.setZoom(false)
+				return m, nil
+			}
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Palette):
+			return m, m.openPalette()
+		case key.Matches(msg, m.keys.Zoom):
+			m.setZoom(!m.zoomed)
+			return m, nil
+		case key.Matches(msg, m.keys.Refresh):
+			return m, m.refreshFocused()
+		case key.Matches(msg, m.keys.RefreshAll):
+			m.setStatus("Refreshing all widgets", false)
+			return m, m.refreshAll()
+		case key.Matches(msg, m.keys.Pause):
+			return m, m.setPaused(!m.paused)
+		}
+		if len(m.pages) > 1 {
+			if i, ok := m.pageKey(msg); ok {
+				return m, m.showPage(i)
+			}
+		}
+		if m.zoomed {
+			// Focus stays on the zoomed widget; everything else goes to it
+			return m.updateFocused(msg)
+		}
+		p := m.page()
+		switch {
+		case key.Matches(msg, m.keys.NextFocus):
+			p.cycleFocus(1)
+			return m, nil
+		case key.Matches(msg, m.keys.PrevFocus):
+			p.cycleFocus(-1)
+			return m, nil
+		}
+		if dir, ok := m.navDirection(msg); ok {
+			p.setFocus(nearest(p.rects, p.focus, dir))
+			return m, nil
+		}
+
+		// Keys go only to the focused widget
+		return m.updateFocused(msg)
+
+	case tea.MouseMsg:
+		if m.showHelp || m.palette != nil {
+			return m, nil
+		}
+		if msg.Y < m.gridTop() {
+			if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+				return m, m.showPage(m.tabAt(msg.X))
+			}
+			return m, nil
+		}
+		if m.zoomed {
+			return m.updateFocused(msg)
+		}
+		p := m.page()
+		i := widgetAt(p.rects, msg.X, msg.Y-m.gridTop())
+		if i < 0 {
+			return m, nil
+		}
+		if tea.MouseEvent(msg).IsWheel() {
+			// Scroll the panel under the cursor, focused or not
+			updatedWidget, cmd := p.widgets[i].Update(msg)
+			p.widgets[i] = updatedWidget
+			return m, cmd
 		}
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			p.setFocus(i)
+		}
+		return m, nil
+
+	case configCheckMsg, configLoadedMsg:
+		return m.updateConfig(msg)
+
+	case widgets.StatusMsg:
+		m.setStatus(msg.Text, msg.Err)
+		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
 		m.updateWidgetSizes()
+		if m.zoomed {
+			m.setZoom(true)
+		}
+		return m, nil
+
+	case widgets.Addressed:
+		// Deliver only to the widget the message belongs to
+		for pi := range m.pages {
+			p := &m.pages[pi]
+			i := p.indexOf(msg.WidgetID())
+			if i < 0 {
+				continue
+			}
+			if refresh, ok := msg.(widgets.RefreshMsg); ok && !refresh.Forced() && m.holds(pi) {
+				// Hold the refresh until the page is shown again or
+				// refreshing resumes
+				p.held[refresh.ID] = refresh
+				return m, nil
+			}
+			updatedWidget, cmd := p.widgets[i].Update(msg)
+			p.widgets[i] = updatedWidget
+			return m, cmd
+		}
 		return m, nil
 	}
 
 	// Update all widgets
 	var cmds []tea.Cmd
-	for i, widget := range m.widgets {
-		updatedWidget, cmd := widget.Update(msg)
-		m.widgets[i] = updatedWidget
-		if cmd != nil {
-			cmds = append(cmds, cmd)
+	for pi := range m.pages {
+		p := &m.pages[pi]
+		for i, widget := range p.widgets {
+			updatedWidget, cmd := widget.Update(msg)
+			p.widgets[i] = updatedWidget
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
 	}
 
@@ -121,87 +238,185 @@ func (m Model) View() string {
 		return "Initializing..."
 	}
 
-	// Calculate grid layout
-	rows := m.config.Layout.Rows
-	cols := m.config.Layout.Cols
-
-	// Calculate widget dimensions
-	widgetWidth := m.width / cols
-	widgetHeight := m.height / rows
-
-	// Build grid
-	var gridRows []string
-	widgetIndex := 0
-
-	for row := 0; row < rows; row++ {
-		var rowWidgets []string
-		for col := 0; col < cols; col++ {
-			if widgetIndex < len(m.widgets) {
-				widget := m.widgets[widgetIndex]
-				widget.SetSize(widgetWidth, widgetHeight)
-				rowWidgets = append(rowWidgets, widget.View())
-				widgetIndex++
-			} else {
-				// Empty placeholder
-				emptyStyle := lipgloss.NewStyle().
-					Width(widgetWidth).
-					Height(widgetHeight)
-				rowWidgets = append(rowWidgets, emptyStyle.Render(""))
-			}
+	var top string
+	if m.gridTop() > 0 {
+		top = m.tabBar() + "\n"
+	}
+
+	if m.showHelp {
+		help := m.footer(shortHelp(m.keys.Help, m.keys.Back, m.keys.Quit))
+		return top + m.helpView(m.width, m.gridHeight()) + "\n" + help
+	}
+	if m.palette != nil {
+		help := m.footer("enter run • ↑/↓ select • esc close")
+		return top + m.paletteView(m.width, m.gridHeight()) + "\n" + help
+	}
+
+	p := m.pages[m.current]
+	if m.zoomed {
+		help := m.footer(shortHelp(m.keys.Help, m.keys.Zoom, m.keys.Back, m.keys.Quit))
+		return top + p.widgets[p.focus].View() + "\n" + help
+	}
+
+	views := make([]string, len(p.widgets))
+	for i, widget := range p.widgets {
+		if i < len(p.rects) && !p.rects[i].Empty() {
+			views[i] = widget.View()
 		}
-		gridRows = append(gridRows, lipgloss.JoinHorizontal(lipgloss.Top, rowWidgets...))
 	}
+	view := layout.Compose(m.width, m.gridHeight(), p.rects, views)
+
+	bindings := []key.Binding{m.keys.Help, m.keys.Palette, m.keys.NextFocus, m.keys.Zoom}
+	if len(m.pages) > 1 {
+		bindings = append(bindings, m.keys.NextPage, m.keys.PrevPage)
+	}
+	help := m.footer(shortHelp(append(bindings, m.keys.Quit)...))
 
-	view := lipgloss.JoinVertical(lipgloss.Left, gridRows...)
+	return top + view + "\n" + help
+}
 
-	// Add help text at the bottom
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+// footer renders the bottom line: the status when there is one, otherwise
+// the help text
+func (m Model) footer(help string) string {
+	style := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Width(m.width).
+		MaxHeight(1).
 		Align(lipgloss.Center)
 
-	help := helpStyle.Render("Press 'q', 'Esc', or 'Ctrl+C' to quit")
+	// The pause indicator stays in view whatever else the line shows
+	var paused string
+	if m.paused {
+		paused = lipgloss.NewStyle().Bold(true).Foreground(m.theme.Focus).Render("⏸ refreshing paused") + " • "
+	}
+	if status := m.statusText(); status != "" {
+		if m.statusErr {
+			return style.Render(paused + lipgloss.NewStyle().Foreground(m.theme.Error).Render(status))
+		}
+		return style.Render(paused + lipgloss.NewStyle().Foreground(m.theme.Success).Render(status))
+	}
+	return style.Render(paused + help)
+}
+
+// gridTop is the first screen line below the tab bar, which is only shown
+// when there is more than one page
+func (m Model) gridTop() int {
+	if len(m.pages) > 1 {
+		return 1
+	}
+	return 0
+}
 
-	return view + "\n" + help
+// gridHeight is the height left for widgets between the tab bar and the
+// help line
+func (m Model) gridHeight() int {
+	return max(m.height-m.gridTop()-1, 0)
 }
 
-// updateWidgetSizes updates the size of all widgets
+// updateWidgetSizes lays out every page, so hidden pages are ready to show
 func (m *Model) updateWidgetSizes() {
-	rows := m.config.Layout.Rows
-	cols := m.config.Layout.Cols
+	for i := range m.pages {
+		m.pages[i].layout(m.width, m.gridHeight())
+	}
+}
 
-	widgetWidth := m.width / cols
-	widgetHeight := m.height / rows
+// updateFocused delivers msg to the focused widget only
+func (m Model) updateFocused(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := m.page()
+	if p.focus < 0 || p.focus >= len(p.widgets) {
+		return m, nil
+	}
+	updatedWidget, cmd := p.widgets[p.focus].Update(msg)
+	p.widgets[p.focus] = updatedWidget
+	return m, cmd
+}
 
-	for _, widget := range m.widgets {
-		widget.SetSize(widgetWidth, widgetHeight)
+// setZoom renders the focused widget at full terminal size, or returns it
+// to its grid cell
+func (m *Model) setZoom(zoomed bool) {
+	p := m.page()
+	if p.focus < 0 || p.focus >= len(p.widgets) {
+		return
+	}
+	m.zoomed = zoomed
+	if !zoomed {
+		p.layout(m.width, m.gridHeight())
+		return
 	}
+	// Leave room for the tab bar and help text
+	p.widgets[p.focus].SetSize(m.width, m.gridHeight())
 }
 
-// LoadConfig loads the application configuration
-func LoadConfig() (*config.Config, error) {
-	configPath, err := config.FindConfigFile()
-	if err != nil {
-		return nil, err
+// holds reports whether scheduled refreshes for the widgets of page i are
+// being held: while refreshing is paused, or while the page is hidden and
+// set to pause in the background
+func (m Model) holds(i int) bool {
+	return m.paused || m.pages[i].paused && i != m.current
+}
+
+// setPaused pauses or resumes scheduled refreshes. Resuming replays the
+// refreshes held meanwhile, except on hidden pages that stay paused.
+func (m *Model) setPaused(paused bool) tea.Cmd {
+	m.paused = paused
+	if paused {
+		// The footer shows the pause for as long as it lasts
+		return nil
+	}
+	m.setStatus("Refreshing resumed", false)
+	var cmds []tea.Cmd
+	for i := range m.pages {
+		if !m.holds(i) {
+			cmds = append(cmds, m.pages[i].release())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// refreshFocused refreshes the focused widget right away
+func (m *Model) refreshFocused() tea.Cmd {
+	p := m.page()
+	if p.focus < 0 || p.focus >= len(p.widgets) {
+		return nil
 	}
+	widget := p.widgets[p.focus]
+	m.setStatus("Refreshing "+widget.Title(), false)
+	return widgets.RefreshNow(widget.ID())
+}
 
-	cfg, err := config.Load(configPath)
+// refreshAll refreshes every widget right away
+func (m *Model) refreshAll() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range m.pages {
+		for _, widget := range p.widgets {
+			cmds = append(cmds, widgets.RefreshNow(widget.ID()))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// LoadConfig loads the configuration from path, or from the file
+// config.FindConfigFile picks when path is empty. Only a config file that was
+// searched for may be missing, which means defaults; a file that doesn't load
+// is an error.
+func LoadConfig(path string) (*config.Config, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = config.FindConfigFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := config.Load(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		// Run with defaults, still watching the path so creating it takes
+		// effect
+		cfg = config.Default()
+		cfg.Path = path
+		return cfg, nil
+	}
 	if err != nil {
-		// Return default config if file doesn't exist
-		return &config.Config{
-			WeatherLocation: "New York",
-			RefreshIntervals: config.RefreshIntervals{
-				Weather: 1800,
-				Github:  300,
-				Gitlab:  300,
-				System:  5,
-				IP:      3600,
-			},
-			GithubRepos: []string{},
-			Layout: config.Layout{
-				Rows: 3,
-				Cols: 3,
-			},
-		}, nil
+		return nil, err
 	}
 
 	return cfg, nil
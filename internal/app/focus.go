@@ -0,0 +1,92 @@
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/layout"
+)
+
+// widgetAt returns the index of the rect containing x, y, or -1
+func widgetAt(rects []layout.Rect, x, y int) int {
+	for i, r := range rects {
+		if r.Contains(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// direction is a spatial focus movement
+type direction int
+
+const (
+	dirUp direction = iota
+	dirDown
+	dirLeft
+	dirRight
+)
+
+// navDirection returns the spatial focus movement a key is bound to
+func (m Model) navDirection(msg tea.KeyMsg) (direction, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		return dirUp, true
+	case key.Matches(msg, m.keys.Down):
+		return dirDown, true
+	case key.Matches(msg, m.keys.Left):
+		return dirLeft, true
+	case key.Matches(msg, m.keys.Right):
+		return dirRight, true
+	}
+	return 0, false
+}
+
+// nearest returns the index of the rect closest to rects[from] in direction
+// dir, or from when there is nothing in that direction. Candidates must lie
+// entirely on that side of the current rect; among them the one with the
+// smallest gap wins, ties going to the one best aligned with the current
+// rect's center. Widgets left off the grid are skipped.
+func nearest(rects []layout.Rect, from int, dir direction) int {
+	if from < 0 || from >= len(rects) {
+		return from
+	}
+	cur := rects[from]
+	cx, cy := cur.X+cur.W/2, cur.Y+cur.H/2
+
+	best, bestGap, bestOffset := from, 0, 0
+	for i, r := range rects {
+		if i == from || r.Empty() {
+			continue
+		}
+
+		var gap, offset int
+		switch dir {
+		case dirUp:
+			gap = cur.Y - (r.Y + r.H)
+			offset = abs(r.X + r.W/2 - cx)
+		case dirDown:
+			gap = r.Y - (cur.Y + cur.H)
+			offset = abs(r.X + r.W/2 - cx)
+		case dirLeft:
+			gap = cur.X - (r.X + r.W)
+			offset = abs(r.Y + r.H/2 - cy)
+		case dirRight:
+			gap = r.X - (cur.X + cur.W)
+			offset = abs(r.Y + r.H/2 - cy)
+		}
+		if gap < 0 {
+			continue
+		}
+		if best == from || gap < bestGap || gap == bestGap && offset < bestOffset {
+			best, bestGap, bestOffset = i, gap, offset
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/widgets"
+)
+
+// shortHelp lists bindings on one line for the footer, skipping disabled ones
+func shortHelp(bindings ...key.Binding) string {
+	var parts []string
+	for _, b := range bindings {
+		if b.Enabled() {
+			parts = append(parts, b.Help().Key+" "+b.Help().Desc)
+		}
+	}
+	return strings.Join(parts, " • ")
+}
+
+// helpSection is a heading and the keys listed under it
+type helpSection struct {
+	title   string
+	entries []key.Help
+}
+
+// helpEntries returns the help of the enabled bindings
+func helpEntries(bindings ...key.Binding) []key.Help {
+	var entries []key.Help
+	for _, b := range bindings {
+		if b.Enabled() {
+			entries = append(entries, b.Help())
+		}
+	}
+	return entries
+}
+
+// helpView renders the help overlay centered in width x height: the
+// dashboard's bindings, then those of the focused widget
+func (m Model) helpView(width, height int) string {
+	k := m.keys
+	global := helpEntries(k.Help, k.Palette, k.NextFocus, k.PrevFocus, k.Up, k.Down, k.Left, k.Right, k.Zoom, k.Refresh, k.RefreshAll, k.Pause)
+	if len(m.pages) > 1 {
+		global = append(global, helpEntries(k.NextPage, k.PrevPage)...)
+		global = append(global, key.Help{Key: fmt.Sprintf("1-%d", min(len(m.pages), 9)), Desc: "show page by number"})
+	}
+	global = append(global, helpEntries(k.Back, k.Quit)...)
+	sections := []helpSection{{title: "Dashboard", entries: global}}
+
+	p := m.pages[m.current]
+	if p.focus >= 0 && p.focus < len(p.widgets) {
+		if helper, ok := p.widgets[p.focus].(widgets.KeyHelper); ok {
+			sections = append(sections, helpSection{
+				title:   p.widgets[p.focus].Title(),
+				entries: helpEntries(helper.KeyBindings()...),
+			})
+		}
+	}
+
+	keyWidth := 0
+	for _, s := range sections {
+		for _, e := range s.entries {
+			keyWidth = max(keyWidth, lipgloss.Width(e.Key))
+		}
+	}
+
+	heading := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Focus)
+	keyStyle := lipgloss.NewStyle().Foreground(m.theme.Title).Width(keyWidth)
+	descStyle := lipgloss.NewStyle()
+	if m.theme.Text != "" {
+		descStyle = descStyle.Foreground(m.theme.Text)
+	}
+
+	var lines []string
+	for i, s := range sections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, heading.Render(s.title))
+		for _, e := range s.entries {
+			lines = append(lines, keyStyle.Render(e.Key)+"  "+descStyle.Render(e.Desc))
+		}
+	}
+
+	box := lipgloss.NewStyle().
+		Border(m.theme.Border).
+		BorderForeground(m.theme.BorderColor).
+		Padding(0, 2).
+		MaxWidth(width).
+		MaxHeight(height).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/internal/config"
+	"github.com/cj3636/gotui/internal/keymap"
+	"github.com/cj3636/gotui/internal/layout"
+	"github.com/cj3636/gotui/internal/theme"
+	"github.com/cj3636/gotui/widgets"
+)
+
+// page is one dashboard page: its widgets, where they sit on screen, and
+// which of them has focus
+type page struct {
+	name    string
+	paused  bool
+	grid    layout.Grid
+	widgets []widgets.Widget
+	cells   []layout.Cell
+	rects   []layout.Rect
+	focus   int
+	// held keeps refreshes that came due while the page was hidden and
+	// paused, keyed by widget ID, to replay when it is shown again
+	held map[string]widgets.RefreshMsg
+}
+
+// newPage builds the widgets listed for a page, in order, drawn in base
+// adjusted by each widget's own theme settings. Widgets found in reuse under
+// their ID are kept as they are instead of being rebuilt.
+func newPage(pc config.PageConfig, base theme.Theme, reuse map[string]widgets.Widget) (page, error) {
+	p := page{
+		name:    pc.Name,
+		paused:  pc.Paused(),
+		grid:    pc.Layout.Grid(),
+		widgets: make([]widgets.Widget, 0, len(pc.Widgets)),
+		cells:   make([]layout.Cell, 0, len(pc.Widgets)),
+		held:    make(map[string]widgets.RefreshMsg),
+	}
+	for _, wc := range pc.Widgets {
+		if widget, ok := reuse[wc.ID]; ok {
+			widget.SetFocused(false)
+			widget.SetTheme(wc.Theme.Resolve(base))
+			widget.SetStaleAfter(time.Duration(wc.StaleAfter) * time.Second)
+			p.widgets = append(p.widgets, widget)
+			p.cells = append(p.cells, wc.Cell())
+			continue
+		}
+		widget, err := widgets.New(widgets.Spec{
+			ID:      wc.ID,
+			Type:    wc.Type,
+			Title:   wc.Title,
+			Refresh: wc.Refresh,
+			Options: wc.Options,
+		})
+		if err != nil {
+			return page{}, fmt.Errorf("page %q: %w", pc.Name, err)
+		}
+		widget.SetTheme(wc.Theme.Resolve(base))
+		widget.SetStaleAfter(time.Duration(wc.StaleAfter) * time.Second)
+		p.widgets = append(p.widgets, widget)
+		p.cells = append(p.cells, wc.Cell())
+	}
+	p.setFocus(0)
+	return p, nil
+}
+
+// indexOf returns the index of the widget with the given ID, or -1
+func (p *page) indexOf(id string) int {
+	for i, widget := range p.widgets {
+		if widget.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// layout places the widgets on the page's grid, sizing each to its cell
+func (p *page) layout(width, height int) {
+	p.rects = p.grid.Place(width, height, p.cells)
+	for i, widget := range p.widgets {
+		if !p.rects[i].Empty() {
+			widget.SetSize(p.rects[i].W, p.rects[i].H)
+		}
+	}
+
+	// Keep focus on a widget that is actually on screen
+	if p.focus >= 0 && p.focus < len(p.rects) && p.rects[p.focus].Empty() {
+		p.cycleFocus(1)
+	}
+}
+
+// setFocus moves keyboard focus to the widget at index i
+func (p *page) setFocus(i int) {
+	if i < 0 || i >= len(p.widgets) {
+		return
+	}
+	if p.focus >= 0 && p.focus < len(p.widgets) {
+		p.widgets[p.focus].SetFocused(false)
+	}
+	p.focus = i
+	p.widgets[i].SetFocused(true)
+}
+
+// cycleFocus moves focus delta steps around the ring of widgets on the grid
+func (p *page) cycleFocus(delta int) {
+	n := len(p.widgets)
+	if n == 0 {
+		return
+	}
+	i := p.focus
+	for range n {
+		i = ((i+delta)%n + n) % n
+		if i >= len(p.rects) || !p.rects[i].Empty() {
+			p.setFocus(i)
+			return
+		}
+	}
+}
+
+// release returns commands replaying the refreshes held while the page was
+// hidden, stamped with the current time
+func (p *page) release() tea.Cmd {
+	var cmds []tea.Cmd
+	for id, msg := range p.held {
+		msg.Time = time.Now()
+		cmds = append(cmds, func() tea.Msg { return msg })
+		delete(p.held, id)
+	}
+	return tea.Batch(cmds...)
+}
+
+// pageKey returns the page a key switches to, if it is a page key
+func (m Model) pageKey(msg tea.KeyMsg) (int, bool) {
+	switch {
+	case key.Matches(msg, m.keys.PrevPage):
+		return (m.current - 1 + len(m.pages)) % len(m.pages), true
+	case key.Matches(msg, m.keys.NextPage):
+		return (m.current + 1) % len(m.pages), true
+	}
+	if k := msg.String(); len(k) == 1 && strings.Contains(keymap.PageKeys, k) {
+		return strings.Index(keymap.PageKeys, k), true
+	}
+	return 0, false
+}
+
+// showPage switches to page i, replaying any refreshes it held while hidden
+func (m *Model) showPage(i int) tea.Cmd {
+	if i < 0 || i >= len(m.pages) || i == m.current {
+		return nil
+	}
+	if m.zoomed {
+		m.setZoom(false)
+	}
+	m.current = i
+	if m.paused {
+		return nil
+	}
+	return m.pages[i].release()
+}
+
+// ShowPage makes the page with the given name, or 1-based number, the one
+// shown first
+func (m *Model) ShowPage(page string) error {
+	for i, p := range m.pages {
+		if strings.EqualFold(p.name, page) {
+			m.current = i
+			return nil
+		}
+	}
+	if n, err := strconv.Atoi(page); err == nil && n >= 1 && n <= len(m.pages) {
+		m.current = n - 1
+		return nil
+	}
+
+	names := make([]string, len(m.pages))
+	for i, p := range m.pages {
+		names[i] = p.name
+	}
+	return fmt.Errorf("no page %q (pages: %s)", page, strings.Join(names, ", "))
+}
+
+// tabLabels returns the text of each tab, numbered for the keys that select it
+func (m Model) tabLabels() []string {
+	labels := make([]string, len(m.pages))
+	for i, p := range m.pages {
+		if i < 9 {
+			labels[i] = fmt.Sprintf(" %d %s ", i+1, p.name)
+		} else {
+			labels[i] = fmt.Sprintf(" %s ", p.name)
+		}
+	}
+	return labels
+}
+
+// tabBar renders the page tabs, highlighting the current page
+func (m Model) tabBar() string {
+	active := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Focus)
+	inactive := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var sb strings.Builder
+	for i, label := range m.tabLabels() {
+		if i == m.current {
+			sb.WriteString(active.Render(label))
+		} else {
+			sb.WriteString(inactive.Render(label))
+		}
+	}
+	return lipgloss.NewStyle().Width(m.width).MaxWidth(m.width).MaxHeight(1).Render(sb.String())
+}
+
+// tabAt returns the index of the tab at column x, or -1
+func (m Model) tabAt(x int) int {
+	start := 0
+	for i, label := range m.tabLabels() {
+		end := start + lipgloss.Width(label)
+		if x >= start && x < end {
+			return i
+		}
+		start = end
+	}
+	return -1
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/widgets"
+)
+
+// paletteKeys move through and act on the palette's list. Everything else
+// is typed into the query.
+var paletteKeys = struct {
+	up, down, run, close key.Binding
+}{
+	up:    key.NewBinding(key.WithKeys("up", "ctrl+p", "ctrl+k")),
+	down:  key.NewBinding(key.WithKeys("down", "ctrl+n", "ctrl+j", "tab")),
+	run:   key.NewBinding(key.WithKeys("enter")),
+	close: key.NewBinding(key.WithKeys("esc", "ctrl+c")),
+}
+
+// paletteRows is the most commands the palette lists at once
+const paletteRows = 10
+
+// paletteCommand is one entry of the command palette
+type paletteCommand struct {
+	title string
+	run   func(m *Model) tea.Cmd
+}
+
+// palette is the open command palette: the query, the commands it was
+// opened with, and those matching the query, best first
+type palette struct {
+	input    textinput.Model
+	commands []paletteCommand
+	matches  []int
+	selected int
+}
+
+func newPalette(commands []paletteCommand) *palette {
+	input := textinput.New()
+	input.Prompt = ": "
+	input.Placeholder = "Type a command"
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	p := &palette{input: input, commands: commands}
+	p.filter()
+	return p
+}
+
+// filter ranks the commands against the query
+func (p *palette) filter() {
+	query := p.input.Value()
+	scores := make(map[int]int)
+	p.matches = p.matches[:0]
+	for i, c := range p.commands {
+		if score, ok := fuzzyMatch(query, c.title); ok {
+			scores[i] = score
+			p.matches = append(p.matches, i)
+		}
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		return scores[p.matches[a]] > scores[p.matches[b]]
+	})
+	p.selected = 0
+}
+
+// fuzzyMatch reports whether the characters of query appear in text in
+// order, ignoring case and spaces in the query, and scores the match.
+// Runs of consecutive characters, characters at the start of a word, and
+// matches that start early score higher.
+func fuzzyMatch(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	t := []rune(strings.ToLower(text))
+
+	score, qi, last, first := 0, 0, -2, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		if first < 0 {
+			first = ti
+		}
+		score++
+		if ti == last+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 2
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	if first > 0 {
+		score -= min(first, 10)
+	}
+	return score, true
+}
+
+// openPalette lists every command available right now and opens the palette
+func (m *Model) openPalette() tea.Cmd {
+	var commands []paletteCommand
+	add := func(title string, run func(m *Model) tea.Cmd) {
+		commands = append(commands, paletteCommand{title: title, run: run})
+	}
+
+	if len(m.pages) > 1 {
+		for i, p := range m.pages {
+			add("Go to page: "+p.name, func(m *Model) tea.Cmd { return m.showPage(i) })
+		}
+	}
+
+	add("Refresh all widgets", func(m *Model) tea.Cmd { return m.refreshAll() })
+	for pi, p := range m.pages {
+		for i, widget := range p.widgets {
+			label := widgetLabel(widget)
+			add("Refresh: "+label, func(*Model) tea.Cmd { return widgets.RefreshNow(widget.ID()) })
+			if i < len(p.rects) && !p.rects[i].Empty() {
+				add("Zoom: "+label, func(m *Model) tea.Cmd {
+					cmd := m.showPage(pi)
+					m.pages[pi].setFocus(i)
+					m.setZoom(true)
+					return cmd
+				})
+			}
+		}
+	}
+
+	if m.config.Path != "" {
+		add("Reload config", func(m *Model) tea.Cmd { return loadConfig(m.config.Path) })
+	}
+	if m.paused {
+		add("Resume refreshing", func(m *Model) tea.Cmd { return m.setPaused(false) })
+	} else {
+		add("Pause refreshing", func(m *Model) tea.Cmd { return m.setPaused(true) })
+	}
+
+	for _, p := range m.pages {
+		for _, widget := range p.widgets {
+			if commander, ok := widget.(widgets.Commander); ok {
+				for _, c := range commander.Commands() {
+					add(c.Title, func(*Model) tea.Cmd { return c.Run() })
+				}
+			}
+		}
+	}
+
+	add("Show help", func(m *Model) tea.Cmd {
+		m.showHelp = true
+		return nil
+	})
+	add("Quit", func(*Model) tea.Cmd { return tea.Quit })
+
+	m.palette = newPalette(commands)
+	return nil
+}
+
+// widgetLabel names a widget for the palette by its title and ID
+func widgetLabel(widget widgets.Widget) string {
+	return fmt.Sprintf("%s (%s)", widget.Title(), widget.ID())
+}
+
+// updatePalette handles a key while the palette is open
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	switch {
+	case key.Matches(msg, paletteKeys.close):
+		m.palette = nil
+		return m, nil
+	case key.Matches(msg, paletteKeys.up):
+		if len(p.matches) > 0 {
+			p.selected = (p.selected - 1 + len(p.matches)) % len(p.matches)
+		}
+		return m, nil
+	case key.Matches(msg, paletteKeys.down):
+		if len(p.matches) > 0 {
+			p.selected = (p.selected + 1) % len(p.matches)
+		}
+		return m, nil
+	case key.Matches(msg, paletteKeys.run):
+		m.palette = nil
+		if len(p.matches) == 0 {
+			return m, nil
+		}
+		cmd := p.commands[p.matches[p.selected]].run(&m)
+		return m, cmd
+	}
+
+	before := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != before {
+		p.filter()
+	}
+	return m, cmd
+}
+
+// paletteView renders the palette at the top of a width x height area
+func (m Model) paletteView(width, height int) string {
+	p := m.palette
+	boxWidth := min(60, max(width-4, 10))
+	innerWidth := boxWidth - 4 // border and padding
+
+	rows := min(paletteRows, max(height-5, 1))
+	start := 0
+	if p.selected >= rows {
+		start = p.selected - rows + 1
+	}
+
+	selected := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Focus)
+	normal := lipgloss.NewStyle()
+	if m.theme.Text != "" {
+		normal = normal.Foreground(m.theme.Text)
+	}
+	muted := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	p.input.Width = innerWidth - lipgloss.Width(p.input.Prompt) - 1
+	lines := []string{p.input.View(), muted.Render(strings.Repeat("─", innerWidth))}
+	if len(p.matches) == 0 {
+		lines = append(lines, muted.Render("No matching commands"))
+	}
+	for i := start; i < len(p.matches) && i < start+rows; i++ {
+		title := p.commands[p.matches[i]].title
+		if i == p.selected {
+			lines = append(lines, selected.MaxWidth(innerWidth).Render("› "+title))
+		} else {
+			lines = append(lines, normal.MaxWidth(innerWidth).Render("  "+title))
+		}
+	}
+	if len(p.matches) > rows {
+		lines = append(lines, muted.Render(fmt.Sprintf("%d of %d", p.selected+1, len(p.matches))))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(m.theme.Border).
+		BorderForeground(m.theme.Focus).
+		Padding(0, 1).
+		Width(boxWidth - 2).
+		MaxHeight(height).
+		Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Top, box)
+}
@@ -0,0 +1,68 @@
+package app
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		match       bool
+	}{
+		{"", "Refresh all", true},
+		{"ref", "Refresh all", true},
+		{"REF", "refresh all", true},
+		{"rall", "Refresh all", true},
+		{"refresh all", "Refresh all", true},
+		{"gtp", "Go to page: Main", true},
+		{"llar", "Refresh all", false},
+		{"zoomx", "Zoom panel", false},
+		{"x", "", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyMatch(tt.query, tt.text); ok != tt.match {
+			t.Errorf("fuzzyMatch(%q, %q) matched = %v, want %v", tt.query, tt.text, ok, tt.match)
+		}
+	}
+}
+
+// Each pair lists a text the query should rank above another
+func TestFuzzyMatchRanking(t *testing.T) {
+	tests := []struct {
+		name, query, better, worse string
+	}{
+		{"consecutive beats scattered", "pau", "Pause refreshing", "Open page: Audio"},
+		{"word starts beat middles", "rp", "Refresh panel", "Sharpen"},
+		{"early beats late", "zoom", "Zoom panel", "Toggle the zoom"},
+	}
+	for _, tt := range tests {
+		better, ok1 := fuzzyMatch(tt.query, tt.better)
+		worse, ok2 := fuzzyMatch(tt.query, tt.worse)
+		if !ok1 || !ok2 {
+			t.Errorf("%s: %q should match both %q and %q", tt.name, tt.query, tt.better, tt.worse)
+			continue
+		}
+		if better <= worse {
+			t.Errorf("%s: %q scores %d on %q and %d on %q", tt.name, tt.query, better, tt.better, worse, tt.worse)
+		}
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	p := newPalette([]paletteCommand{
+		{title: "Toggle the zoom"},
+		{title: "Refresh all"},
+		{title: "Zoom panel"},
+	})
+	if len(p.matches) != 3 {
+		t.Fatalf("empty query matches %v, want every command", p.matches)
+	}
+
+	p.input.SetValue("zoom")
+	p.filter()
+	var titles []string
+	for _, i := range p.matches {
+		titles = append(titles, p.commands[i].title)
+	}
+	if len(titles) != 2 || titles[0] != "Zoom panel" || titles[1] != "Toggle the zoom" {
+		t.Errorf("matches for %q = %q, want Zoom panel then Toggle the zoom", "zoom", titles)
+	}
+}
@@ -0,0 +1,256 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/config"
+	"github.com/cj3636/gotui/internal/secret"
+	"github.com/cj3636/gotui/internal/theme"
+	"github.com/cj3636/gotui/widgets"
+)
+
+// configCheckInterval is how often the config file is checked for changes
+const configCheckInterval = time.Second
+
+// statusDuration is how long informational status messages stay up; errors
+// stay until the next successful reload
+const statusDuration = 3 * time.Second
+
+// fileStamp identifies one version of a file. A missing file has the zero
+// stamp.
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
+
+// configCheckMsg carries the config file's stamp at the latest check
+type configCheckMsg struct {
+	stamp fileStamp
+}
+
+// configLoadedMsg is the result of reloading the config file
+type configLoadedMsg struct {
+	cfg *config.Config
+	err error
+}
+
+// watchConfig checks the config file for changes after configCheckInterval
+func (m Model) watchConfig() tea.Cmd {
+	path := m.config.Path
+	if path == "" {
+		return nil
+	}
+	return tea.Tick(configCheckInterval, func(time.Time) tea.Msg {
+		return configCheckMsg{stamp: statFile(path)}
+	})
+}
+
+// loadConfig reads the config file in the background
+func loadConfig(path string) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := config.Load(path)
+		return configLoadedMsg{cfg: cfg, err: err}
+	}
+}
+
+// setStatus shows text in the status line
+func (m *Model) setStatus(text string, isErr bool) {
+	m.status = secret.Redact(strings.ReplaceAll(strings.TrimSpace(text), "\n", "; "))
+	m.statusErr = isErr
+	m.statusAt = time.Now()
+}
+
+// warningSummary appends the first of a config's warnings, and how many
+// there are, to a status message
+func warningSummary(text string, warnings []config.Problem) string {
+	switch len(warnings) {
+	case 0:
+		return text
+	case 1:
+		return fmt.Sprintf("%s with a warning: %s", text, warnings[0])
+	}
+	return fmt.Sprintf("%s with %d warnings, first: %s", text, len(warnings), warnings[0])
+}
+
+// logWarnings logs each of a config's warnings, since the status line only
+// has room for the first
+func logWarnings(warnings []config.Problem) {
+	for _, w := range warnings {
+		msg := w.Message
+		if w.Line > 0 {
+			msg = fmt.Sprintf("line %d: %s", w.Line, msg)
+		}
+		slog.Warn("config", "err", msg)
+	}
+}
+
+// statusText returns the status to show, or "" once an informational
+// message has expired
+func (m Model) statusText() string {
+	if m.status == "" || !m.statusErr && time.Since(m.statusAt) > statusDuration {
+		return ""
+	}
+	return m.status
+}
+
+// applyConfig replaces the pages and widgets with those of cfg. Widgets whose
+// entry is unchanged apart from its position keep running with their state;
+// the rest are built and started fresh.
+func (m *Model) applyConfig(cfg *config.Config) (tea.Cmd, error) {
+	oldSpecs := make(map[string]config.WidgetConfig)
+	oldWidgets := make(map[string]widgets.Widget)
+	focused := make(map[string]bool)
+	var held []widgets.RefreshMsg
+	for pi, pc := range m.config.Pages {
+		p := &m.pages[pi]
+		for i, wc := range pc.Widgets {
+			oldSpecs[wc.ID] = wc
+			oldWidgets[wc.ID] = p.widgets[i]
+		}
+		if p.focus >= 0 && p.focus < len(p.widgets) {
+			focused[p.widgets[p.focus].ID()] = true
+		}
+		for _, msg := range p.held {
+			held = append(held, msg)
+		}
+	}
+
+	reuse := make(map[string]widgets.Widget)
+	for _, pc := range cfg.Pages {
+		for _, wc := range pc.Widgets {
+			if old, ok := oldSpecs[wc.ID]; ok && sameWidget(old, wc) {
+				reuse[wc.ID] = oldWidgets[wc.ID]
+			}
+		}
+	}
+
+	th := cfg.Theme.Resolve(theme.Default())
+	pages := make([]page, 0, len(cfg.Pages))
+	for _, pc := range cfg.Pages {
+		p, err := newPage(pc, th, reuse)
+		if err != nil {
+			// Building pages moved focus around on the widgets they reused
+			// and restyled them
+			for i := range m.pages {
+				for j, widget := range m.pages[i].widgets {
+					wc := m.config.Pages[i].Widgets[j]
+					widget.SetFocused(false)
+					widget.SetTheme(wc.Theme.Resolve(m.theme))
+					widget.SetStaleAfter(time.Duration(wc.StaleAfter) * time.Second)
+				}
+				m.pages[i].setFocus(m.pages[i].focus)
+			}
+			return nil, err
+		}
+		pages = append(pages, p)
+	}
+
+	var cmds []tea.Cmd
+	for pi := range pages {
+		p := &pages[pi]
+		for i, widget := range p.widgets {
+			if focused[widget.ID()] {
+				p.setFocus(i)
+			}
+			if _, ok := reuse[widget.ID()]; !ok {
+				cmds = append(cmds, widget.Init())
+			}
+		}
+	}
+
+	// Stay on the same page if it still exists
+	current := min(m.current, len(pages)-1)
+	oldName := m.pages[m.current].name
+	for i, p := range pages {
+		if p.name == oldName {
+			current = i
+			break
+		}
+	}
+
+	m.config = cfg
+	m.theme = th
+	m.keys = cfg.KeyMap()
+	m.pages = pages
+	m.current = current
+
+	// Kept widgets still need the refreshes they were holding
+	for _, msg := range held {
+		if _, ok := reuse[msg.ID]; !ok {
+			continue
+		}
+		for pi := range m.pages {
+			p := &m.pages[pi]
+			if p.indexOf(msg.ID) < 0 {
+				continue
+			}
+			if m.holds(pi) {
+				p.held[msg.ID] = msg
+			} else {
+				msg.Time = time.Now()
+				cmds = append(cmds, func() tea.Msg { return msg })
+			}
+		}
+	}
+
+	m.updateWidgetSizes()
+	if m.zoomed {
+		m.setZoom(true)
+	}
+	return tea.Batch(cmds...), nil
+}
+
+// sameWidget reports whether two entries describe the same widget. Moving or
+// restyling a widget, or changing when it counts as stale, doesn't require
+// rebuilding it.
+func sameWidget(a, b config.WidgetConfig) bool {
+	a.Row, a.Col, a.RowSpan, a.ColSpan = b.Row, b.Col, b.RowSpan, b.ColSpan
+	a.Theme = b.Theme
+	a.StaleAfter = b.StaleAfter
+	return reflect.DeepEqual(a, b)
+}
+
+// updateConfig handles the config watcher's messages
+func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case configCheckMsg:
+		cmds := []tea.Cmd{m.watchConfig()}
+		if msg.stamp != m.stamp {
+			m.stamp = msg.stamp
+			cmds = append(cmds, loadConfig(m.config.Path))
+		}
+		return m, tea.Batch(cmds...)
+
+	case configLoadedMsg:
+		if msg.err != nil {
+			slog.Error("config not reloaded", "err", msg.err)
+			m.setStatus(fmt.Sprintf("Config not reloaded: %v", msg.err), true)
+			return m, nil
+		}
+		cmd, err := m.applyConfig(msg.cfg)
+		if err != nil {
+			slog.Error("config not reloaded", "err", err)
+			m.setStatus(fmt.Sprintf("Config not reloaded: %v", err), true)
+			return m, nil
+		}
+		slog.Info("config reloaded")
+		logWarnings(msg.cfg.Warnings)
+		m.setStatus(warningSummary("Config reloaded", msg.cfg.Warnings), false)
+		return m, cmd
+	}
+	return m, nil
+}
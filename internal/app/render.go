@@ -0,0 +1,73 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Render runs the dashboard without a terminal and returns a single frame of
+// width x height. It starts every widget and feeds their messages back to
+// them until each widget on the page being shown has its first data, or
+// timeout passes. The IDs of widgets still loading at that point are
+// returned with the frame.
+func Render(m Model, width, height int, timeout time.Duration) (string, []string) {
+	msgs := make(chan tea.Msg)
+	done := make(chan struct{})
+	defer close(done)
+
+	// Commands run in the background like they would under tea.Program;
+	// whatever is still running when the frame is taken is abandoned
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					run(c)
+				}
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-done:
+			}
+		}()
+	}
+
+	sized, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m = sized.(Model)
+	run(m.Init())
+
+	deadline := time.After(timeout)
+	for len(m.loading()) > 0 {
+		select {
+		case msg := <-msgs:
+			if msg == nil {
+				continue
+			}
+			updated, cmd := m.Update(msg)
+			m = updated.(Model)
+			run(cmd)
+		case <-deadline:
+			return m.View(), m.loading()
+		}
+	}
+	return m.View(), nil
+}
+
+// loading returns the IDs of the widgets on screen that don't have their
+// first data yet
+func (m Model) loading() []string {
+	var ids []string
+	p := m.pages[m.current]
+	for i, widget := range p.widgets {
+		if i < len(p.rects) && !p.rects[i].Empty() && !widget.Loaded() {
+			ids = append(ids, widget.ID())
+		}
+	}
+	return ids
+}
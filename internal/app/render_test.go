@@ -0,0 +1,64 @@
+package app
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRenderGolden draws one plain frame of a dashboard whose widgets only
+// read local files and compares it with testdata/render.golden. Run
+// "go test ./internal/app -update" to accept a deliberate change.
+func TestRenderGolden(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	// Settings from the environment, or warnings about retired variables
+	// in the status line, would change the frame
+	for _, key := range []string{
+		"GITHUB_TOKEN", "GITLAB_TOKEN", "WTTR_LOCATION", "WTTR_UNITS", "WTTR_MOON_LOCATION", "MARKDOWN_PATH",
+		"WTTR_VIEW", "WTTR_MOON_VIEW", "WTTR_MOON_UNITS",
+	} {
+		t.Setenv(key, "")
+	}
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "WIDGET_HEIGHT_") {
+			t.Setenv(name, "")
+		}
+	}
+
+	cfg, err := LoadConfig(filepath.Join("testdata", "render.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := NewModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, loading := Render(model, 72, 16, 5*time.Second)
+	if len(loading) > 0 {
+		t.Fatalf("widgets still loading: %v", loading)
+	}
+
+	golden := filepath.Join("testdata", "render.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(frame), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame != string(want) {
+		t.Errorf("frame differs from %s:\n%s\nwant:\n%s", golden, frame, want)
+	}
+}
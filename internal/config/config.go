@@ -1,9 +1,16 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/internal/keymap"
+	"github.com/cj3636/gotui/internal/layout"
+	"github.com/cj3636/gotui/internal/theme"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,12 +19,156 @@ type Config struct {
 	GithubToken      string           `yaml:"github_token"`
 	GitlabToken      string           `yaml:"gitlab_token"`
 	WeatherLocation  string           `yaml:"weather_location"`
+	WeatherUnits     string           `yaml:"weather_units"`
+	MoonLocation     string           `yaml:"moon_location"`
 	RefreshIntervals RefreshIntervals `yaml:"refresh_intervals"`
+	StaleAfter       int              `yaml:"stale_after"`
 	GithubRepos      []string         `yaml:"github_repos"`
 	GitlabProjects   []string         `yaml:"gitlab_projects"`
 	TextFile         string           `yaml:"text_file"`
 	MarkdownFile     string           `yaml:"markdown_file"`
 	Layout           Layout           `yaml:"layout"`
+	Theme            ThemeConfig      `yaml:"theme"`
+	Keys             map[string]Keys  `yaml:"keys"`
+	Widgets          []WidgetConfig   `yaml:"widgets"`
+	Pages            []PageConfig     `yaml:"pages"`
+
+	// Path is the file the configuration was loaded from, if any
+	Path string `yaml:"-"`
+	// Warnings lists problems found while loading that didn't stop it
+	Warnings []Problem `yaml:"-"`
+}
+
+// Background settings for pages that are not being shown
+const (
+	// BackgroundUpdate keeps hidden widgets refreshing on schedule
+	BackgroundUpdate = "update"
+	// BackgroundPause holds refreshes until the page is shown again
+	BackgroundPause = "pause"
+)
+
+// PageConfig is one dashboard page with its own grid and widgets. Without a
+// pages list, the top-level layout and widgets form a single page.
+type PageConfig struct {
+	Name       string         `yaml:"name"`
+	Layout     Layout         `yaml:"layout"`
+	Background string         `yaml:"background"`
+	Widgets    []WidgetConfig `yaml:"widgets"`
+}
+
+// Paused reports whether the page's widgets stop refreshing while it is hidden
+func (p PageConfig) Paused() bool {
+	return p.Background == BackgroundPause
+}
+
+// applyDefaults names the page, inherits the top-level layout when the page
+// has none, and fills in its widget entries
+func (p *PageConfig) applyDefaults(c *Config, index int) {
+	if p.Name == "" {
+		p.Name = fmt.Sprintf("Page %d", index+1)
+	}
+	if p.Layout.isZero() {
+		p.Layout = c.Layout
+	}
+	p.Layout.applyDefaults()
+	if p.Background == "" {
+		p.Background = BackgroundUpdate
+	}
+	for i := range p.Widgets {
+		p.Widgets[i].applyDefaults(c)
+	}
+}
+
+// WidgetConfig is one entry of the widgets list. Keys other than the common
+// ones below are collected into Options and interpreted by the widget type.
+type WidgetConfig struct {
+	Type    string `yaml:"type"`
+	ID      string `yaml:"id"`
+	Title   string `yaml:"title"`
+	Refresh int    `yaml:"refresh"`
+	// StaleAfter is how many seconds after its last update the widget's
+	// data counts as stale
+	StaleAfter int `yaml:"stale_after"`
+	// Row and Col place the widget on the grid, counting from 1. Widgets
+	// without both fill the first free cell in reading order.
+	Row     int `yaml:"row"`
+	Col     int `yaml:"col"`
+	RowSpan int `yaml:"row_span"`
+	ColSpan int `yaml:"col_span"`
+	// Theme adjusts the dashboard theme for this widget alone
+	Theme   ThemeConfig    `yaml:"theme"`
+	Options map[string]any `yaml:",inline"`
+}
+
+// Cell returns the widget's position on the layout grid
+func (w WidgetConfig) Cell() layout.Cell {
+	cell := layout.Cell{Row: -1, Col: -1, RowSpan: w.RowSpan, ColSpan: w.ColSpan}
+	if w.Row > 0 && w.Col > 0 {
+		cell.Row, cell.Col = w.Row-1, w.Col-1
+	}
+	return cell
+}
+
+// ThemeConfig picks a built-in theme by name and adjusts its border style
+// and colors. It can be written as just the theme name.
+type ThemeConfig struct {
+	Name   string            `yaml:"name"`
+	Border string            `yaml:"border"`
+	Colors map[string]string `yaml:"colors"`
+}
+
+// UnmarshalYAML accepts a theme name in place of the full mapping
+func (t *ThemeConfig) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		t.Name = n.Value
+		return nil
+	case yaml.MappingNode:
+	default:
+		return &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: theme must be a theme name or a mapping of name, border and colors", n.Line),
+		}}
+	}
+	type plain ThemeConfig
+	return n.Decode((*plain)(t))
+}
+
+// Resolve applies the settings to base: a named theme replaces it, then the
+// border style and colors given override it. Validation rejects unknown
+// names, so they are ignored here.
+func (t ThemeConfig) Resolve(base theme.Theme) theme.Theme {
+	if named, ok := theme.Named(t.Name); ok {
+		base = named
+	}
+	if border, ok := theme.BorderNamed(t.Border); ok {
+		base.Border = border
+	}
+	for name, value := range t.Colors {
+		base.SetColor(name, lipgloss.Color(value))
+	}
+	return base
+}
+
+// Keys lists the keys bound to a dashboard action. A single key can be
+// written without the list.
+type Keys []string
+
+// UnmarshalYAML accepts a single key in place of a list
+func (k *Keys) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*k = Keys{n.Value}
+		return nil
+	}
+	return n.Decode((*[]string)(k))
+}
+
+// KeyMap returns the default key bindings with the configured ones in place
+func (c *Config) KeyMap() keymap.KeyMap {
+	keys := keymap.Default()
+	for action, list := range c.Keys {
+		keys.Set(action, list)
+	}
+	return keys
 }
 
 // RefreshIntervals defines how often each widget refreshes (in seconds)
@@ -29,36 +180,341 @@ type RefreshIntervals struct {
 	IP      int `yaml:"ip"`
 }
 
-// Layout defines the grid layout for widgets
+// applyDefaults fills intervals left unset
+func (r *RefreshIntervals) applyDefaults() {
+	if r.Weather == 0 {
+		r.Weather = 1800
+	}
+	if r.Github == 0 {
+		r.Github = 300
+	}
+	if r.Gitlab == 0 {
+		r.Gitlab = 300
+	}
+	if r.System == 0 {
+		r.System = 5
+	}
+	if r.IP == 0 {
+		r.IP = 3600
+	}
+}
+
+// For returns the refresh interval configured for a widget type, or 0 when
+// the type has no entry
+func (r RefreshIntervals) For(widgetType string) int {
+	switch widgetType {
+	case "weather", "moon":
+		return r.Weather
+	case "github":
+		return r.Github
+	case "gitlab":
+		return r.Gitlab
+	case "system":
+		return r.System
+	case "ip":
+		return r.IP
+	}
+	return 0
+}
+
+// Layout defines the grid layout for widgets. Rows and columns share the
+// screen by weight (1 when unset); a non-zero size fixes a track at that
+// many cells instead.
 type Layout struct {
-	Rows int `yaml:"rows"`
-	Cols int `yaml:"cols"`
+	Rows       int   `yaml:"rows"`
+	Cols       int   `yaml:"cols"`
+	RowWeights []int `yaml:"row_weights"`
+	ColWeights []int `yaml:"col_weights"`
+	RowSizes   []int `yaml:"row_sizes"`
+	ColSizes   []int `yaml:"col_sizes"`
+}
+
+func (l Layout) isZero() bool {
+	return l.Rows == 0 && l.Cols == 0 &&
+		len(l.RowWeights) == 0 && len(l.ColWeights) == 0 &&
+		len(l.RowSizes) == 0 && len(l.ColSizes) == 0
+}
+
+// applyDefaults sizes the grid to fit the longest weight or size list,
+// falling back to 3x3
+func (l *Layout) applyDefaults() {
+	if l.Rows == 0 {
+		l.Rows = max(len(l.RowWeights), len(l.RowSizes))
+	}
+	if l.Cols == 0 {
+		l.Cols = max(len(l.ColWeights), len(l.ColSizes))
+	}
+	if l.Rows == 0 {
+		l.Rows = 3
+	}
+	if l.Cols == 0 {
+		l.Cols = 3
+	}
+}
+
+// Grid returns the layout grid described by the configuration
+func (l Layout) Grid() layout.Grid {
+	return layout.Grid{
+		Rows: tracks(l.Rows, l.RowWeights, l.RowSizes),
+		Cols: tracks(l.Cols, l.ColWeights, l.ColSizes),
+	}
+}
+
+func tracks(n int, weights, sizes []int) []layout.Track {
+	list := make([]layout.Track, n)
+	for i := range list {
+		if i < len(weights) {
+			list[i].Weight = weights[i]
+		}
+		if i < len(sizes) {
+			list[i].Size = sizes[i]
+		}
+	}
+	return list
+}
+
+// envFallbacks maps environment variables to the settings they fill in when
+// the YAML leaves them empty
+var envFallbacks = []struct {
+	key   string
+	field func(*Config) *string
+}{
+	{"GITHUB_TOKEN", func(c *Config) *string { return &c.GithubToken }},
+	{"GITLAB_TOKEN", func(c *Config) *string { return &c.GitlabToken }},
+	{"WTTR_LOCATION", func(c *Config) *string { return &c.WeatherLocation }},
+	{"WTTR_UNITS", func(c *Config) *string { return &c.WeatherUnits }},
+	{"WTTR_MOON_LOCATION", func(c *Config) *string { return &c.MoonLocation }},
+	{"MARKDOWN_PATH", func(c *Config) *string { return &c.MarkdownFile }},
+}
+
+// retiredEnv lists environment variables earlier versions read that no
+// setting replaces, with what to do instead. They are reported as warnings
+// rather than ignored silently.
+var retiredEnv = []struct {
+	key, hint string
+}{
+	{"WTTR_VIEW", "the weather widget always shows its compact summary"},
+	{"WTTR_MOON_VIEW", "the moon widget always shows its compact summary"},
+	{"WTTR_MOON_UNITS", "the moon widget has no units to set"},
 }
 
-// Load reads the configuration from a YAML file
+// widgetHeightPrefix starts the retired per-widget height variables, such
+// as WIDGET_HEIGHT_WEATHER
+const widgetHeightPrefix = "WIDGET_HEIGHT_"
+
+// Default returns the configuration used when no config file exists, with
+// environment variables applied
+func Default() *Config {
+	config := &Config{
+		GithubRepos: []string{},
+	}
+	config.Warnings = config.applyEnv()
+	config.applyDefaults()
+	// Legacy widget lists never carry explicit ids, so this cannot fail
+	_ = config.assignIDs()
+
+	// Tokens from the environment may be references too. There is no file
+	// to fail, so problems resolving them are only warnings.
+	c := &checker{keyLines: make(map[string]int)}
+	for _, p := range c.secrets(config) {
+		p.Warning = true
+		config.Warnings = append(config.Warnings, p)
+	}
+	return config
+}
+
+// Load reads the configuration from a YAML file. Settings the file leaves
+// empty fall back to environment variables, then to built-in defaults.
+// Secret settings given as env:, file: or cmd: references are replaced by
+// the values they refer to. Unknown keys, out-of-range values, malformed
+// options and secrets that can't be resolved are errors, returned together
+// as a *ValidationError; problems that don't stop the file from loading are
+// kept in Warnings.
 func Load(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	config, problems := parse(data, true)
+	if hasErrors(problems) {
+		var errs []Problem
+		for _, p := range problems {
+			if !p.Warning {
+				errs = append(errs, p)
+			}
+		}
+		return nil, &ValidationError{File: filename, Problems: errs}
+	}
+
+	config.Path = filename
+	config.Warnings = problems
+
+	return config, nil
+}
+
+// applyEnv fills settings left empty by the YAML from the environment and
+// returns warnings for retired variables that are set
+func (c *Config) applyEnv() []Problem {
+	for _, fb := range envFallbacks {
+		field := fb.field(c)
+		if *field != "" {
+			continue
+		}
+		if v := strings.TrimSpace(os.Getenv(fb.key)); v != "" {
+			*field = v
+		}
+	}
+
+	var warnings []Problem
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, Problem{Message: fmt.Sprintf(format, args...), Warning: true})
+	}
+	for _, r := range retiredEnv {
+		if os.Getenv(r.key) != "" {
+			warn("%s is no longer read: %s", r.key, r.hint)
+		}
+	}
+	var heights []string
+	for _, kv := range os.Environ() {
+		if name, value, _ := strings.Cut(kv, "="); strings.HasPrefix(name, widgetHeightPrefix) && value != "" {
+			heights = append(heights, name)
+		}
+	}
+	sort.Strings(heights)
+	for _, name := range heights {
+		warn("%s is no longer read: size rows with row_weights or row_sizes under layout", name)
+	}
+	return warnings
+}
+
+// applyDefaults fills in anything still unset
+func (c *Config) applyDefaults() {
+	if c.WeatherLocation == "" {
+		c.WeatherLocation = "New York"
+	}
+	c.Layout.applyDefaults()
+	c.RefreshIntervals.applyDefaults()
+	if len(c.Pages) == 0 {
+		if len(c.Widgets) == 0 {
+			c.Widgets = c.legacyWidgets()
+		}
+		c.Pages = []PageConfig{{Name: "Main", Layout: c.Layout, Widgets: c.Widgets}}
+	}
+	for i := range c.Pages {
+		c.Pages[i].applyDefaults(c, i)
+	}
+}
+
+// legacyWidgets builds the widget list from the top-level settings used
+// before the widgets list existed, in the order the dashboard always used
+func (c *Config) legacyWidgets() []WidgetConfig {
+	list := []WidgetConfig{{Type: "clock"}, {Type: "calendar"}}
+	if c.WeatherLocation != "" {
+		list = append(list, WidgetConfig{Type: "weather"})
+	}
+	if c.MoonLocation != "" {
+		list = append(list, WidgetConfig{Type: "moon"})
+	}
+	if len(c.GithubRepos) > 0 {
+		list = append(list, WidgetConfig{Type: "github"})
+	}
+	if len(c.GitlabProjects) > 0 {
+		list = append(list, WidgetConfig{Type: "gitlab"})
+	}
+	list = append(list, WidgetConfig{Type: "system"}, WidgetConfig{Type: "ip"}, WidgetConfig{Type: "smart"})
+	if c.TextFile != "" {
+		list = append(list, WidgetConfig{Type: "text"})
+	}
+	if c.MarkdownFile != "" {
+		list = append(list, WidgetConfig{Type: "markdown"})
+	}
+	return list
+}
+
+// assignIDs gives every widget without an explicit id one derived from its
+// type: the first weather widget is "weather", the second "weather-2", and
+// so on. Explicit ids must be unique across all pages.
+func (c *Config) assignIDs() error {
+	var all []*WidgetConfig
+	for p := range c.Pages {
+		for i := range c.Pages[p].Widgets {
+			all = append(all, &c.Pages[p].Widgets[i])
+		}
 	}
 
-	// Set defaults
-	if config.WeatherLocation == "" {
-		config.WeatherLocation = "New York"
+	used := make(map[string]bool, len(all))
+	for _, w := range all {
+		if w.ID == "" {
+			continue
+		}
+		if used[w.ID] {
+			return fmt.Errorf("widget id %q is used more than once", w.ID)
+		}
+		used[w.ID] = true
 	}
-	if config.Layout.Rows == 0 {
-		config.Layout.Rows = 3
+
+	counts := make(map[string]int)
+	for _, w := range all {
+		if w.ID != "" {
+			continue
+		}
+		for w.ID == "" || used[w.ID] {
+			counts[w.Type]++
+			w.ID = w.Type
+			if counts[w.Type] > 1 {
+				w.ID = fmt.Sprintf("%s-%d", w.Type, counts[w.Type])
+			}
+		}
+		used[w.ID] = true
 	}
-	if config.Layout.Cols == 0 {
-		config.Layout.Cols = 3
+	return nil
+}
+
+// staleRefreshes is how many refresh intervals may pass without an update
+// before a widget's data counts as stale, unless stale_after says otherwise
+const staleRefreshes = 3
+
+// applyDefaults fills the entry's refresh interval and staleness threshold,
+// and any option the entry leaves out that has a top-level setting
+func (w *WidgetConfig) applyDefaults(c *Config) {
+	if w.Refresh == 0 {
+		w.Refresh = c.RefreshIntervals.For(w.Type)
+	}
+	if w.StaleAfter == 0 {
+		w.StaleAfter = c.StaleAfter
+	}
+	if w.StaleAfter == 0 {
+		w.StaleAfter = staleRefreshes * w.Refresh
+	}
+	if w.Options == nil {
+		w.Options = map[string]any{}
 	}
 
-	return &config, nil
+	var fallbacks map[string]any
+	switch w.Type {
+	case "weather":
+		fallbacks = map[string]any{"location": c.WeatherLocation, "units": c.WeatherUnits}
+	case "moon":
+		location := c.MoonLocation
+		if location == "" {
+			location = c.WeatherLocation
+		}
+		fallbacks = map[string]any{"location": location}
+	case "github":
+		fallbacks = map[string]any{"token": c.GithubToken, "repos": c.GithubRepos}
+	case "gitlab":
+		fallbacks = map[string]any{"token": c.GitlabToken, "projects": c.GitlabProjects}
+	case "text":
+		fallbacks = map[string]any{"file": c.TextFile}
+	case "markdown":
+		fallbacks = map[string]any{"file": c.MarkdownFile}
+	}
+	for key, value := range fallbacks {
+		if _, ok := w.Options[key]; !ok {
+			w.Options[key] = value
+		}
+	}
 }
 
 // FindConfigFile looks for config.yaml in common locations
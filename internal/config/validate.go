@@ -0,0 +1,606 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cj3636/gotui/internal/keymap"
+	"github.com/cj3636/gotui/internal/layout"
+	"github.com/cj3636/gotui/internal/secret"
+	"github.com/cj3636/gotui/internal/theme"
+	"github.com/cj3636/gotui/widgets"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is one issue found in a config file
+type Problem struct {
+	// Line is the line in the file the problem refers to, or 0 when it
+	// isn't tied to one
+	Line    int
+	Message string
+	// Warning marks problems that don't stop the config from loading
+	Warning bool
+}
+
+func (p Problem) String() string {
+	s := p.Message
+	if p.Warning {
+		s = "warning: " + s
+	}
+	if p.Line > 0 {
+		s = fmt.Sprintf("line %d: %s", p.Line, s)
+	}
+	return s
+}
+
+// ValidationError reports the errors that stopped a config file from loading
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s is invalid:", e.File)
+	for _, p := range e.Problems {
+		sb.WriteString("\n  " + p.String())
+	}
+	return sb.String()
+}
+
+// Check reads a config file and reports every problem in it, errors and
+// warnings alike, ordered by line. The error is only set when the file
+// can't be read.
+func Check(filename string) ([]Problem, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	// Secrets are left alone: the machine checking the file may well not
+	// have them
+	_, problems := parse(data, false)
+	return problems, nil
+}
+
+// hasErrors reports whether any problem is an error
+func hasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if !p.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	yamlLineRE    = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	unknownKeysRE = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+)
+
+// yamlProblems turns a YAML syntax or decoding error into problems
+func yamlProblems(err error) []Problem {
+	var msgs []string
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		msgs = typeErr.Errors
+	} else {
+		msgs = []string{err.Error()}
+	}
+
+	problems := make([]Problem, 0, len(msgs))
+	for _, msg := range msgs {
+		p := Problem{Message: strings.TrimPrefix(msg, "yaml: ")}
+		if m := yamlLineRE.FindStringSubmatch(msg); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+			p.Message = m[2]
+		}
+		if m := unknownKeysRE.FindStringSubmatch(p.Message); m != nil {
+			p.Message = fmt.Sprintf("unknown key %q", m[1])
+		}
+		problems = append(problems, p)
+	}
+	return problems
+}
+
+// parse decodes config data strictly and checks it, then, if resolve is
+// set and nothing is wrong so far, replaces secret references with their
+// values. The config is nil when the data isn't valid YAML.
+func parse(data []byte, resolve bool) (*Config, []Problem) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, yamlProblems(err)
+	}
+
+	var config Config
+	var problems []Problem
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		problems = append(problems, yamlProblems(err)...)
+	}
+
+	c := &checker{ids: make(map[string]int), keyLines: make(map[string]int)}
+	if len(root.Content) > 0 {
+		c.root(root.Content[0])
+	}
+	problems = append(problems, c.problems...)
+
+	problems = append(problems, config.applyEnv()...)
+	config.applyDefaults()
+	// Duplicate ids are the only error here, and the checker has already
+	// reported those with their lines
+	if err := config.assignIDs(); err != nil && !hasErrors(problems) {
+		problems = append(problems, Problem{Message: err.Error()})
+	}
+	if !hasErrors(problems) {
+		problems = append(problems, c.semantic(&config)...)
+	}
+	if resolve && !hasErrors(problems) {
+		problems = append(problems, c.secrets(&config)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return &config, problems
+}
+
+// checker walks the YAML tree for problems the decoder can't see: values
+// out of range, unknown widget types and options, and duplicate ids
+type checker struct {
+	problems []Problem
+	ids      map[string]int
+	// widgetNodes holds the widget entries of each page, in order; without
+	// pages, the top-level list is the only page
+	widgetNodes  [][]*yaml.Node
+	hasPagesList bool
+	// keyLines holds the line of each top-level setting's value
+	keyLines map[string]int
+}
+
+// widgetNode returns the YAML entry of widget i on page p, or nil
+func (c *checker) widgetNode(p, i int) *yaml.Node {
+	if p < len(c.widgetNodes) && i < len(c.widgetNodes[p]) {
+		return c.widgetNodes[p][i]
+	}
+	return nil
+}
+
+// nodeLine returns the line of n, or 0 for nil
+func nodeLine(n *yaml.Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.Line
+}
+
+func (c *checker) errorf(n *yaml.Node, format string, args ...any) {
+	c.problems = append(c.problems, Problem{Line: n.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) warnf(n *yaml.Node, format string, args ...any) {
+	c.problems = append(c.problems, Problem{Line: nodeLine(n), Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// pairs calls fn for each key and value of a mapping node
+func pairs(n *yaml.Node, fn func(key, value *yaml.Node)) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i], n.Content[i+1])
+	}
+}
+
+// intValue returns the node's value if it is a number; the decoder reports
+// anything else
+func intValue(n *yaml.Node) (int, bool) {
+	if n.Kind != yaml.ScalarNode {
+		return 0, false
+	}
+	v, err := strconv.Atoi(n.Value)
+	return v, err == nil
+}
+
+// atLeast rejects numbers below min
+func (c *checker) atLeast(n *yaml.Node, name string, min int) {
+	if v, ok := intValue(n); ok && v < min {
+		c.errorf(n, "%s must be at least %d, got %d", name, min, v)
+	}
+}
+
+// interval rejects refresh intervals that would make a widget spin
+func (c *checker) interval(n *yaml.Node, name string) {
+	if v, ok := intValue(n); ok && v <= 0 {
+		c.errorf(n, "%s must be a positive number of seconds, got %d", name, v)
+	}
+}
+
+func (c *checker) root(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		c.errorf(n, "config must be a mapping of settings")
+		return
+	}
+
+	var widgetsKey, widgetsValue *yaml.Node
+	pairs(n, func(key, value *yaml.Node) {
+		c.keyLines[key.Value] = value.Line
+		switch key.Value {
+		case "github_token", "gitlab_token":
+			if err := secret.Check(value.Value); err != nil {
+				c.errorf(value, "%s: %v", key.Value, err)
+			}
+		case "refresh_intervals":
+			pairs(value, func(k, v *yaml.Node) {
+				c.interval(v, "refresh_intervals."+k.Value)
+			})
+		case "stale_after":
+			c.interval(value, "stale_after")
+		case "layout":
+			c.layout(value)
+		case "theme":
+			c.theme(value)
+		case "keys":
+			c.keys(value)
+		case "widgets":
+			widgetsKey, widgetsValue = key, value
+		case "pages":
+			c.pages(value)
+		case "github_repos":
+			c.option(value, "github", "repos")
+		case "gitlab_projects":
+			c.option(value, "gitlab", "projects")
+		case "weather_units":
+			c.option(value, "weather", "units")
+		}
+	})
+
+	if widgetsKey == nil {
+		return
+	}
+	if c.hasPagesList {
+		c.warnf(widgetsKey, "widgets is ignored when pages is set")
+		return
+	}
+	c.widgetNodes = [][]*yaml.Node{c.widgetList(widgetsValue)}
+}
+
+func (c *checker) layout(n *yaml.Node) {
+	pairs(n, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "rows", "cols":
+			c.atLeast(value, key.Value, 1)
+		case "row_weights", "col_weights":
+			for _, item := range value.Content {
+				c.atLeast(item, key.Value+" entries", 1)
+			}
+		case "row_sizes", "col_sizes":
+			for _, item := range value.Content {
+				c.atLeast(item, key.Value+" entries", 0)
+			}
+		}
+	})
+}
+
+// theme checks a theme name, or a mapping of name, border and colors
+func (c *checker) theme(n *yaml.Node) {
+	// The decoder reports anything other than a name or a mapping
+	if n.Kind == yaml.ScalarNode {
+		c.themeName(n)
+		return
+	}
+
+	pairs(n, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "name":
+			c.themeName(value)
+		case "border":
+			if _, ok := theme.BorderNamed(value.Value); !ok {
+				c.errorf(value, "unknown border style %q (known styles: %s)", value.Value, strings.Join(theme.BorderNames(), ", "))
+			}
+		case "colors":
+			pairs(value, func(k, v *yaml.Node) {
+				if !slices.Contains(theme.ColorNames(), k.Value) {
+					c.errorf(k, "unknown theme color %q (known colors: %s)", k.Value, strings.Join(theme.ColorNames(), ", "))
+					return
+				}
+				if err := theme.CheckColor(v.Value); err != nil {
+					c.errorf(v, "colors.%s: %v", k.Value, err)
+				}
+			})
+		default:
+			c.errorf(key, "unknown key %q", key.Value)
+		}
+	})
+}
+
+func (c *checker) themeName(n *yaml.Node) {
+	if _, ok := theme.Named(n.Value); !ok {
+		c.errorf(n, "unknown theme %q (known themes: %s)", n.Value, strings.Join(theme.Names(), ", "))
+	}
+}
+
+// keys checks key bindings: actions must exist, page keys stay fixed, and no
+// key may trigger two actions once the defaults are filled in
+func (c *checker) keys(n *yaml.Node) {
+	configured := make(map[string][]*yaml.Node)
+	pairs(n, func(key, value *yaml.Node) {
+		if _, ok := keymap.DefaultKeys(key.Value); !ok {
+			c.errorf(key, "unknown action %q (known actions: %s)", key.Value, strings.Join(keymap.Actions(), ", "))
+			return
+		}
+
+		var items []*yaml.Node
+		switch value.Kind {
+		case yaml.ScalarNode:
+			items = []*yaml.Node{value}
+		case yaml.SequenceNode:
+			items = value.Content
+		}
+		var valid []*yaml.Node
+		for _, item := range items {
+			switch {
+			case item.Kind != yaml.ScalarNode:
+				// The decoder reports it
+			case item.Value == "":
+				c.errorf(item, "keys.%s: key must not be empty", key.Value)
+			case len(item.Value) == 1 && strings.Contains(keymap.PageKeys, item.Value):
+				c.errorf(item, "keys.%s: %q shows a page and can't be rebound", key.Value, item.Value)
+			default:
+				valid = append(valid, item)
+			}
+		}
+		configured[key.Value] = valid
+	})
+
+	owner := make(map[string]string)
+	ownerNode := make(map[string]*yaml.Node)
+	for _, action := range keymap.Actions() {
+		if items, ok := configured[action]; ok {
+			for _, item := range items {
+				if prev, taken := owner[item.Value]; taken && prev != action {
+					c.errorf(item, "key %q is already bound to %s", item.Value, prev)
+					continue
+				}
+				owner[item.Value] = action
+				ownerNode[item.Value] = item
+			}
+			continue
+		}
+		defaults, _ := keymap.DefaultKeys(action)
+		for _, k := range defaults {
+			if prev, taken := owner[k]; taken && prev != action {
+				c.errorf(ownerNode[k], "key %q is %s's default key; bind %s to other keys too", k, action, action)
+				continue
+			}
+			owner[k] = action
+		}
+	}
+}
+
+func (c *checker) pages(n *yaml.Node) {
+	if n.Kind != yaml.SequenceNode || len(n.Content) == 0 {
+		return
+	}
+	c.hasPagesList = true
+	for _, page := range n.Content {
+		var nodes []*yaml.Node
+		pairs(page, func(key, value *yaml.Node) {
+			switch key.Value {
+			case "layout":
+				c.layout(value)
+			case "widgets":
+				nodes = c.widgetList(value)
+			case "background":
+				if value.Value != BackgroundUpdate && value.Value != BackgroundPause {
+					c.errorf(value, "background must be %q or %q, got %q", BackgroundUpdate, BackgroundPause, value.Value)
+				}
+			}
+		})
+		c.widgetNodes = append(c.widgetNodes, nodes)
+	}
+}
+
+// widgetList checks each entry of a widgets list and returns the entries
+func (c *checker) widgetList(n *yaml.Node) []*yaml.Node {
+	if n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, entry := range n.Content {
+		c.widget(entry)
+	}
+	return n.Content
+}
+
+func (c *checker) widget(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		c.errorf(n, "widget entry must be a mapping with a type")
+		return
+	}
+
+	var typ *yaml.Node
+	pairs(n, func(key, value *yaml.Node) {
+		if key.Value == "type" {
+			typ = value
+		}
+	})
+	if typ == nil {
+		c.errorf(n, "widget entry has no type")
+		return
+	}
+	if _, ok := widgets.OptionsFor(typ.Value); !ok {
+		c.errorf(typ, "unknown widget type %q (known types: %s)", typ.Value, strings.Join(widgets.Types(), ", "))
+		return
+	}
+
+	pairs(n, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "type", "title":
+		case "id":
+			if line, dup := c.ids[value.Value]; dup {
+				c.errorf(value, "widget id %q is already used on line %d", value.Value, line)
+			} else {
+				c.ids[value.Value] = value.Line
+			}
+		case "refresh", "stale_after":
+			c.interval(value, key.Value)
+			if t, _ := widgets.Lookup(typ.Value); key.Value == "refresh" && t.IgnoresRefresh {
+				c.warnf(key, "%s widgets don't use refresh; it has no effect", typ.Value)
+			}
+		case "row", "col", "row_span", "col_span":
+			c.atLeast(value, key.Value, 1)
+		case "theme":
+			c.theme(value)
+		default:
+			if _, ok := widgets.LookupOption(typ.Value, key.Value); !ok {
+				c.errorf(key, "unknown option %q for %s widget", key.Value, typ.Value)
+				return
+			}
+			c.option(value, typ.Value, key.Value)
+		}
+	})
+}
+
+// option checks a value against the option a widget type declares
+func (c *checker) option(n *yaml.Node, typ, name string) {
+	opt, ok := widgets.LookupOption(typ, name)
+	if !ok {
+		return
+	}
+
+	var items []*yaml.Node
+	switch {
+	case n.Kind == yaml.ScalarNode:
+		items = []*yaml.Node{n}
+	case n.Kind == yaml.SequenceNode && opt.Kind == widgets.StringListOption:
+		items = n.Content
+	default:
+		c.errorf(n, "%s must be %s", name, kindName(opt.Kind))
+		return
+	}
+
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			c.errorf(item, "%s must be %s", name, kindName(opt.Kind))
+			continue
+		}
+		if opt.Kind == widgets.IntOption {
+			if _, err := strconv.Atoi(item.Value); err != nil {
+				c.errorf(item, "%s must be %s", name, kindName(opt.Kind))
+				continue
+			}
+		}
+		if opt.Check != nil {
+			if err := opt.Check(item.Value); err != nil {
+				c.errorf(item, "%s: %v", name, err)
+			}
+		}
+		if opt.Secret {
+			if err := secret.Check(item.Value); err != nil {
+				c.errorf(item, "%s: %v", name, err)
+			}
+		}
+	}
+}
+
+func kindName(kind widgets.OptionKind) string {
+	switch kind {
+	case widgets.IntOption:
+		return "a number"
+	case widgets.StringListOption:
+		return "a list of strings"
+	}
+	return "a string"
+}
+
+// semantic warns about settings that load but probably don't do what was
+// meant, once defaults and environment fallbacks are applied
+func (c *checker) semantic(config *Config) []Problem {
+	c.problems = nil
+	for pi, page := range config.Pages {
+		node := func(i int) *yaml.Node { return c.widgetNode(pi, i) }
+
+		cells := make([]layout.Cell, len(page.Widgets))
+		for i, wc := range page.Widgets {
+			cells[i] = wc.Cell()
+		}
+		// Any size works; only whether a widget gets a cell matters
+		rects := page.Layout.Grid().Place(page.Layout.Cols*100, page.Layout.Rows*100, cells)
+		for i, r := range rects {
+			if r.Empty() {
+				c.warnf(node(i), "%s widget %q doesn't fit on the %dx%d grid and won't be shown",
+					page.Widgets[i].Type, page.Widgets[i].ID, page.Layout.Rows, page.Layout.Cols)
+			}
+		}
+
+		for i, wc := range page.Widgets {
+			opts := widgets.Options(wc.Options)
+			switch wc.Type {
+			case "github":
+				if len(opts.Strings("repos")) == 0 {
+					c.warnf(node(i), "github widget %q has no repos", wc.ID)
+				}
+				if opts.String("token", "") == "" {
+					c.warnf(node(i), "github widget %q has no token; unauthenticated requests are limited to 60 an hour", wc.ID)
+				}
+			case "gitlab":
+				if len(opts.Strings("projects")) == 0 {
+					c.warnf(node(i), "gitlab widget %q has no projects", wc.ID)
+				}
+			case "text", "markdown":
+				file := opts.String("file", "")
+				if file == "" {
+					c.warnf(node(i), "%s widget %q has no file", wc.Type, wc.ID)
+				} else if _, err := os.Stat(file); err != nil {
+					c.warnf(node(i), "%s widget %q: %v", wc.Type, wc.ID, err)
+				}
+			}
+		}
+	}
+	return c.problems
+}
+
+// secrets replaces the secret settings of config with the values they refer
+// to. Each distinct value is resolved once, so a reference shared through a
+// top-level fallback runs its command once and fails once, at its first use.
+func (c *checker) secrets(config *Config) []Problem {
+	c.problems = nil
+	resolved := make(map[string]string)
+	failed := make(map[string]bool)
+	resolve := func(value string, line int, name string) string {
+		if v, ok := resolved[value]; ok || failed[value] {
+			return v
+		}
+		v, err := secret.Resolve(value)
+		if err != nil {
+			failed[value] = true
+			c.problems = append(c.problems, Problem{Line: line, Message: fmt.Sprintf("%s: %v", name, err)})
+			return ""
+		}
+		resolved[value] = v
+		return v
+	}
+
+	config.GithubToken = resolve(config.GithubToken, c.keyLines["github_token"], "github_token")
+	config.GitlabToken = resolve(config.GitlabToken, c.keyLines["gitlab_token"], "gitlab_token")
+	for pi, page := range config.Pages {
+		for i, wc := range page.Widgets {
+			options, _ := widgets.OptionsFor(wc.Type)
+			for _, opt := range options {
+				value, ok := wc.Options[opt.Name].(string)
+				if !opt.Secret || !ok || value == "" {
+					continue
+				}
+				name := fmt.Sprintf("%s widget %q %s", wc.Type, wc.ID, opt.Name)
+				wc.Options[opt.Name] = resolve(value, nodeLine(c.widgetNode(pi, i)), name)
+			}
+		}
+	}
+	return c.problems
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProblems(t *testing.T) {
+	for _, key := range []string{"GITHUB_TOKEN", "GITLAB_TOKEN", "WTTR_LOCATION", "WTTR_UNITS", "WTTR_MOON_LOCATION", "MARKDOWN_PATH"} {
+		t.Setenv(key, "")
+	}
+
+	tests := []struct {
+		name string
+		yaml string
+		want []Problem
+	}{
+		{
+			name: "valid",
+			yaml: "weather_location: Berlin\nwidgets:\n  - type: clock\n  - type: weather\n    units: m\n",
+		},
+		{
+			name: "syntax error",
+			yaml: "weather_location: [1\n",
+			want: []Problem{{Line: 1, Message: "did not find expected ',' or ']'"}},
+		},
+		{
+			name: "unknown keys",
+			yaml: "weather_location: Berlin\nunknown_top: 1\nlayout:\n  columns: 2\n",
+			want: []Problem{
+				{Line: 2, Message: `unknown key "unknown_top"`},
+				{Line: 4, Message: `unknown key "columns"`},
+			},
+		},
+		{
+			name: "widget entries",
+			yaml: "widgets:\n" +
+				"  - type: clock\n" +
+				"    bogus: 1\n" +
+				"  - type: nope\n" +
+				"  - type: github\n" +
+				"    repos: [\"noslash\"]\n" +
+				"  - type: clock\n" +
+				"    id: a\n" +
+				"  - type: calendar\n" +
+				"    id: a\n" +
+				"    row: 0\n",
+			want: []Problem{
+				{Line: 3, Message: `unknown option "bogus" for clock widget`},
+				{Line: 4, Message: `unknown widget type "nope" (known types: calendar, clock, github, gitlab, ip, log, markdown, moon, smart, system, text, weather)`},
+				{Line: 6, Message: `repos: "noslash" is not an owner/repo name`},
+				{Line: 10, Message: `widget id "a" is already used on line 8`},
+				{Line: 11, Message: "row must be at least 1, got 0"},
+			},
+		},
+		{
+			name: "intervals",
+			yaml: "refresh_intervals:\n  github: 0\nstale_after: -5\nwidgets:\n  - type: ip\n    refresh: -1\n",
+			want: []Problem{
+				{Line: 2, Message: "refresh_intervals.github must be a positive number of seconds, got 0"},
+				{Line: 3, Message: "stale_after must be a positive number of seconds, got -5"},
+				{Line: 6, Message: "refresh must be a positive number of seconds, got -1"},
+			},
+		},
+		{
+			name: "settings checked like options",
+			yaml: "weather_units: x\ntheme: nosuch\nkeys:\n  quit: r\ngithub_token: \"env:\"\n",
+			want: []Problem{
+				{Line: 1, Message: `units: "x" is not m, u or M`},
+				{Line: 2, Message: `unknown theme "nosuch" (known themes: dark, high-contrast, light, solarized)`},
+				{Line: 4, Message: `key "r" is already bound to refresh`},
+				{Line: 5, Message: `github_token: "env:" needs a variable name after the colon`},
+			},
+		},
+		{
+			name: "warnings",
+			yaml: "widgets:\n  - type: clock\n    refresh: 5\n  - type: text\n    file: /nonexistent/gotui-test.txt\n",
+			want: []Problem{
+				{Line: 3, Message: "clock widgets don't use refresh; it has no effect", Warning: true},
+				{Line: 4, Message: `text widget "text": stat /nonexistent/gotui-test.txt: no such file or directory`, Warning: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := parse([]byte(tt.yaml), false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse() problems:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetiredEnv(t *testing.T) {
+	t.Setenv("WTTR_VIEW", "Fq1")
+	t.Setenv("WIDGET_HEIGHT_WEATHER", "2")
+
+	_, got := parse([]byte("weather_location: Berlin\n"), false)
+	want := []Problem{
+		{Message: "WTTR_VIEW is no longer read: the weather widget always shows its compact summary", Warning: true},
+		{Message: "WIDGET_HEIGHT_WEATHER is no longer read: size rows with row_weights or row_sizes under layout", Warning: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse() problems:\n%v\nwant:\n%v", got, want)
+	}
+}
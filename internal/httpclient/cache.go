@@ -0,0 +1,149 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxCachedBody is the largest response body the cache keeps
+const maxCachedBody = 1 << 20
+
+// varyHeaders are the request headers that select a different response
+// for the same URL. Tokens are only ever kept hashed, as part of the key.
+var varyHeaders = []string{"Accept", "Authorization", "Private-Token"}
+
+// cachedResponse is a response that can be revalidated: its body and
+// headers, and the validators sent back to the server
+type cachedResponse struct {
+	header       http.Header
+	body         []byte
+	etag         string
+	lastModified string
+}
+
+// cacheTransport makes GET requests conditional on what it fetched before.
+// When the server answers 304 Not Modified, which GitHub doesn't count
+// against the rate limit, the cached response is returned in its place.
+// The cache is shared, so widgets fetching the same URL with the same
+// credentials revalidate each other's responses.
+type cacheTransport struct {
+	base    http.RoundTripper
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+func newCacheTransport(base http.RoundTripper) *cacheTransport {
+	return &cacheTransport{base: base, entries: make(map[string]*cachedResponse)}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests that carry their own validators handle 304 themselves
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	t.mu.Lock()
+	cached := t.entries[key]
+	t.mu.Unlock()
+
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		return cached.response(req, resp.Header), nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			t.forget(key)
+			return resp, nil
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		if len(body) > maxCachedBody {
+			// Too big to keep: hand back what was read and the rest
+			t.forget(key)
+			resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+			return resp, nil
+		}
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		t.mu.Lock()
+		t.entries[key] = &cachedResponse{
+			header:       resp.Header.Clone(),
+			body:         body,
+			etag:         etag,
+			lastModified: lastModified,
+		}
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *cacheTransport) forget(key string) {
+	t.mu.Lock()
+	delete(t.entries, key)
+	t.mu.Unlock()
+}
+
+// response rebuilds the cached response for req. Headers of the 304, such
+// as rate limit counts, replace the cached ones.
+func (c *cachedResponse) response(req *http.Request, fresh http.Header) *http.Response {
+	header := c.header.Clone()
+	for name, values := range fresh {
+		if name != "Content-Length" {
+			header[name] = values
+		}
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a response by URL and the request headers it varies
+// with, hashed so no token is kept in memory longer than the request
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.URL.String())
+	for _, name := range varyHeaders {
+		io.WriteString(h, "\x00"+req.Header.Get(name))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCloser reads from one reader and closes another
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// validatingServer serves body with an ETag, answers matching
+// If-None-Match requests with 304, and records what it was asked
+type validatingServer struct {
+	mu       sync.Mutex
+	body     string
+	etag     string
+	requests []http.Header
+}
+
+func (s *validatingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Header.Clone())
+
+	w.Header().Set("X-RateLimit-Remaining", r.Header.Get("X-Test-Remaining"))
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	io.WriteString(w, s.body)
+}
+
+func (s *validatingServer) set(body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag = body, etag
+}
+
+func get(t *testing.T, client *http.Client, url string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestCacheTransportRevalidates(t *testing.T) {
+	srv := &validatingServer{body: "v1", etag: `"1"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := &http.Client{Transport: newCacheTransport(http.DefaultTransport)}
+
+	resp, body := get(t, client, ts.URL, map[string]string{"X-Test-Remaining": "59"})
+	if resp.StatusCode != http.StatusOK || body != "v1" {
+		t.Fatalf("first fetch = %d %q, want 200 v1", resp.StatusCode, body)
+	}
+
+	// Unchanged: the 304 comes back as the cached 200, with fresh headers
+	resp, body = get(t, client, ts.URL, map[string]string{"X-Test-Remaining": "58"})
+	if resp.StatusCode != http.StatusOK || body != "v1" {
+		t.Fatalf("revalidated fetch = %d %q, want 200 v1", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "58" {
+		t.Errorf("revalidated fetch has X-RateLimit-Remaining %q, want the 304's 58", got)
+	}
+	if got := srv.requests[1].Get("If-None-Match"); got != `"1"` {
+		t.Errorf("second request sent If-None-Match %q, want %q", got, `"1"`)
+	}
+
+	// Changed: the new body replaces the cached one
+	srv.set("v2", `"2"`)
+	if _, body = get(t, client, ts.URL, nil); body != "v2" {
+		t.Errorf("after a change got %q, want v2", body)
+	}
+	if _, body = get(t, client, ts.URL, nil); body != "v2" {
+		t.Errorf("revalidating the change got %q, want v2", body)
+	}
+}
+
+func TestCacheTransportKeysByCredentials(t *testing.T) {
+	srv := &validatingServer{body: "private", etag: `"p"`}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := &http.Client{Transport: newCacheTransport(http.DefaultTransport)}
+
+	get(t, client, ts.URL, map[string]string{"Authorization": "token a"})
+	get(t, client, ts.URL, map[string]string{"Authorization": "token b"})
+	get(t, client, ts.URL, map[string]string{"Authorization": "token a"})
+
+	if got := srv.requests[1].Get("If-None-Match"); got != "" {
+		t.Errorf("another token's request sent If-None-Match %q", got)
+	}
+	if got := srv.requests[2].Get("If-None-Match"); got != `"p"` {
+		t.Errorf("same token's request sent If-None-Match %q, want %q", got, `"p"`)
+	}
+}
+
+func TestCacheTransportSkips(t *testing.T) {
+	srv := &validatingServer{body: "no validators"}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	cache := newCacheTransport(http.DefaultTransport)
+	client := &http.Client{Transport: cache}
+
+	// Responses without validators aren't kept
+	get(t, client, ts.URL, nil)
+	if len(cache.entries) != 0 {
+		t.Errorf("kept %d responses without ETag or Last-Modified", len(cache.entries))
+	}
+
+	// Requests with validators of their own pass through untouched
+	srv.set("no validators", `"x"`)
+	resp, _ := get(t, client, ts.URL, map[string]string{"If-None-Match": `"x"`})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("own If-None-Match got %d, want 304", resp.StatusCode)
+	}
+
+	// Bodies over the limit are passed on whole but not kept
+	big := strings.Repeat("x", maxCachedBody+10)
+	srv.set(big, `"big"`)
+	if _, body := get(t, client, ts.URL+"/big", nil); body != big {
+		t.Errorf("large body came back with %d bytes, want %d", len(body), len(big))
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("kept a body over %d bytes", maxCachedBody)
+	}
+}
@@ -0,0 +1,161 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout bounds a whole request, retries included. Widgets pass a context
+// with this deadline to every request they make.
+const Timeout = 30 * time.Second
+
+// Retry settings. The wait before retry n is retryBase * 2^n plus up to as
+// much again of jitter, capped at retryMax.
+const (
+	attempts  = 3
+	retryBase = 500 * time.Millisecond
+	retryMax  = 5 * time.Second
+)
+
+var (
+	mu        sync.RWMutex
+	userAgent = "gotui"
+)
+
+// SetUserAgent sets the User-Agent header sent with every request
+func SetUserAgent(ua string) {
+	mu.Lock()
+	defer mu.Unlock()
+	userAgent = ua
+}
+
+func currentUserAgent() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return userAgent
+}
+
+// client is shared by every network widget so connections are reused
+// across refreshes
+var client = &http.Client{
+	Transport: newCacheTransport(&retryTransport{base: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   4,
+		ForceAttemptHTTP2:     true,
+	}}),
+	Timeout: Timeout,
+}
+
+// Client returns the shared client. It revalidates responses it has seen
+// with ETag and Last-Modified, sets the user agent, and retries idempotent
+// requests that time out or fail with a 5xx status.
+func Client() *http.Client {
+	return client
+}
+
+// Context returns a context that expires after Timeout
+func Context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), Timeout)
+}
+
+// Fetch returns the body of url, fetched with the shared client and
+// cancelled with ctx. Statuses other than 200 OK are errors.
+func Fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", req.URL.Host, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// retryTransport sets the user agent and retries failed attempts with
+// exponential backoff and jitter
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", currentUserAgent())
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == attempts-1 || !retryable(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			slog.Debug("retrying request", "host", req.URL.Host, "status", resp.Status)
+			resp.Body.Close()
+		} else {
+			slog.Debug("retrying request", "host", req.URL.Host, "err", err)
+		}
+
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether an attempt failed in a way another attempt
+// might not: a timeout or a server error. Only requests that are safe to
+// repeat and whose body can be replayed are retried.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// backoff returns how long to wait before retrying after attempt
+func backoff(attempt int) time.Duration {
+	d := retryBase << attempt
+	d += time.Duration(rand.Int63n(int64(d)))
+	return min(d, retryMax)
+}
@@ -0,0 +1,104 @@
+package keymap
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the dashboard's own key bindings. Keys it doesn't bind go to
+// the focused widget.
+type KeyMap struct {
+	Quit       key.Binding
+	Back       key.Binding
+	Help       key.Binding
+	Palette    key.Binding
+	Zoom       key.Binding
+	Refresh    key.Binding
+	RefreshAll key.Binding
+	Pause      key.Binding
+	NextFocus  key.Binding
+	PrevFocus  key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Left       key.Binding
+	Right      key.Binding
+	NextPage   key.Binding
+	PrevPage   key.Binding
+}
+
+// actions lists the bindings by the names a config uses for them, with their
+// default keys, and the descriptions help shows for them
+var actions = []struct {
+	name  string
+	desc  string
+	keys  []string
+	field func(*KeyMap) *key.Binding
+}{
+	{"help", "help", []string{"?"}, func(k *KeyMap) *key.Binding { return &k.Help }},
+	{"palette", "commands", []string{":", "ctrl+p"}, func(k *KeyMap) *key.Binding { return &k.Palette }},
+	{"next_focus", "next panel", []string{"tab"}, func(k *KeyMap) *key.Binding { return &k.NextFocus }},
+	{"prev_focus", "previous panel", []string{"shift+tab"}, func(k *KeyMap) *key.Binding { return &k.PrevFocus }},
+	{"focus_up", "focus up", []string{"up", "k"}, func(k *KeyMap) *key.Binding { return &k.Up }},
+	{"focus_down", "focus down", []string{"down", "j"}, func(k *KeyMap) *key.Binding { return &k.Down }},
+	{"focus_left", "focus left", []string{"left", "h"}, func(k *KeyMap) *key.Binding { return &k.Left }},
+	{"focus_right", "focus right", []string{"right", "l"}, func(k *KeyMap) *key.Binding { return &k.Right }},
+	{"zoom", "zoom", []string{"z"}, func(k *KeyMap) *key.Binding { return &k.Zoom }},
+	{"refresh", "refresh panel", []string{"r"}, func(k *KeyMap) *key.Binding { return &k.Refresh }},
+	{"refresh_all", "refresh all", []string{"R"}, func(k *KeyMap) *key.Binding { return &k.RefreshAll }},
+	{"pause", "pause/resume refreshing", []string{"p"}, func(k *KeyMap) *key.Binding { return &k.Pause }},
+	{"next_page", "next page", []string{"]"}, func(k *KeyMap) *key.Binding { return &k.NextPage }},
+	{"prev_page", "previous page", []string{"["}, func(k *KeyMap) *key.Binding { return &k.PrevPage }},
+	{"back", "back", []string{"esc"}, func(k *KeyMap) *key.Binding { return &k.Back }},
+	{"quit", "quit", []string{"q", "ctrl+c"}, func(k *KeyMap) *key.Binding { return &k.Quit }},
+}
+
+// PageKeys are the keys that show pages 1 to 9. They can't be rebound.
+const PageKeys = "123456789"
+
+// Default returns the default bindings
+func Default() KeyMap {
+	var k KeyMap
+	for _, a := range actions {
+		*a.field(&k) = binding(a.keys, a.desc)
+	}
+	return k
+}
+
+func binding(keys []string, desc string) key.Binding {
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), desc))
+}
+
+// Actions returns the names of the bindings
+func Actions() []string {
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = a.name
+	}
+	return names
+}
+
+// DefaultKeys returns the keys an action is bound to by default
+func DefaultKeys(action string) ([]string, bool) {
+	for _, a := range actions {
+		if a.name == action {
+			return a.keys, true
+		}
+	}
+	return nil, false
+}
+
+// Set binds an action to keys, replacing its defaults; no keys disables it.
+// It reports whether the action exists.
+func (k *KeyMap) Set(action string, keys []string) bool {
+	for _, a := range actions {
+		if a.name == action {
+			*a.field(k) = binding(keys, a.desc)
+			return true
+		}
+	}
+	return false
+}
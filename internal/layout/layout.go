@@ -0,0 +1,240 @@
+package layout
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Track sizes one row or column of the grid
+type Track struct {
+	// Size is a fixed size in cells; 0 makes the track weighted
+	Size int
+	// Weight is the track's share of the space left after fixed tracks;
+	// values below 1 count as 1
+	Weight int
+}
+
+// Grid describes the rows and columns widgets are placed on
+type Grid struct {
+	Rows []Track
+	Cols []Track
+}
+
+// Cell is a widget's position on the grid. Row and Col are zero-based; a
+// negative Row or Col asks Place to find the first free spot.
+type Cell struct {
+	Row, Col         int
+	RowSpan, ColSpan int
+}
+
+// Rect is a region of the screen in cells. A zero Rect means the widget
+// did not fit on the grid.
+type Rect struct {
+	X, Y, W, H int
+}
+
+// Contains reports whether the cell at x, y lies inside r
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
+
+// Empty reports whether r covers no cells
+func (r Rect) Empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Distribute splits total cells among tracks. Fixed tracks get their size,
+// shrinking when they don't fit; the remainder is shared by weight, with the
+// cells lost to integer division going to the tracks with the largest
+// fractional share. The result always sums to total, so the grid fills the
+// screen exactly.
+func Distribute(total int, tracks []Track) []int {
+	sizes := make([]int, len(tracks))
+	if len(tracks) == 0 || total <= 0 {
+		return sizes
+	}
+
+	remaining := total
+	weightSum := 0
+	for i, t := range tracks {
+		if t.Size > 0 {
+			sizes[i] = min(t.Size, remaining)
+			remaining -= sizes[i]
+		} else {
+			weightSum += weightOf(t)
+		}
+	}
+
+	if weightSum == 0 {
+		// Only fixed tracks: the last one absorbs any slack
+		sizes[len(sizes)-1] += remaining
+		return sizes
+	}
+
+	type share struct{ index, remainder int }
+	var shares []share
+	given := 0
+	for i, t := range tracks {
+		if t.Size > 0 {
+			continue
+		}
+		w := weightOf(t)
+		sizes[i] = remaining * w / weightSum
+		given += sizes[i]
+		shares = append(shares, share{i, remaining * w % weightSum})
+	}
+
+	sort.SliceStable(shares, func(a, b int) bool {
+		return shares[a].remainder > shares[b].remainder
+	})
+	for k := 0; k < remaining-given; k++ {
+		sizes[shares[k%len(shares)].index]++
+	}
+	return sizes
+}
+
+func weightOf(t Track) int {
+	if t.Weight < 1 {
+		return 1
+	}
+	return t.Weight
+}
+
+// Place computes the screen area of each cell within width x height. Cells
+// with an explicit position are placed first; the rest fill the first free
+// spot in reading order that fits their span. The result is aligned with
+// cells, and cells that don't fit get an empty Rect.
+func (g Grid) Place(width, height int, cells []Cell) []Rect {
+	nrows, ncols := len(g.Rows), len(g.Cols)
+	rects := make([]Rect, len(cells))
+	if nrows == 0 || ncols == 0 {
+		return rects
+	}
+
+	rowSizes := Distribute(height, g.Rows)
+	colSizes := Distribute(width, g.Cols)
+	rowStarts := offsets(rowSizes)
+	colStarts := offsets(colSizes)
+
+	occupied := make([][]bool, nrows)
+	for r := range occupied {
+		occupied[r] = make([]bool, ncols)
+	}
+	free := func(row, col, rowSpan, colSpan int) bool {
+		for r := row; r < row+rowSpan; r++ {
+			for c := col; c < col+colSpan; c++ {
+				if occupied[r][c] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	place := func(i, row, col, rowSpan, colSpan int) {
+		for r := row; r < row+rowSpan; r++ {
+			for c := col; c < col+colSpan; c++ {
+				occupied[r][c] = true
+			}
+		}
+		rects[i] = Rect{
+			X: colStarts[col],
+			Y: rowStarts[row],
+			W: colStarts[col+colSpan] - colStarts[col],
+			H: rowStarts[row+rowSpan] - rowStarts[row],
+		}
+	}
+
+	spans := make([][2]int, len(cells))
+	for i, c := range cells {
+		spans[i] = [2]int{max(c.RowSpan, 1), max(c.ColSpan, 1)}
+	}
+
+	// Explicit positions first, clipping spans at the grid edge
+	for i, c := range cells {
+		if c.Row < 0 || c.Col < 0 || c.Row >= nrows || c.Col >= ncols {
+			continue
+		}
+		rowSpan := min(spans[i][0], nrows-c.Row)
+		colSpan := min(spans[i][1], ncols-c.Col)
+		if free(c.Row, c.Col, rowSpan, colSpan) {
+			place(i, c.Row, c.Col, rowSpan, colSpan)
+		}
+	}
+
+	// Then everything else in reading order
+	for i, c := range cells {
+		if c.Row >= 0 && c.Col >= 0 {
+			continue
+		}
+		rowSpan := min(spans[i][0], nrows)
+		colSpan := min(spans[i][1], ncols)
+	search:
+		for r := 0; r+rowSpan <= nrows; r++ {
+			for col := 0; col+colSpan <= ncols; col++ {
+				if free(r, col, rowSpan, colSpan) {
+					place(i, r, col, rowSpan, colSpan)
+					break search
+				}
+			}
+		}
+	}
+
+	return rects
+}
+
+// offsets returns the start of each track plus the end of the last one
+func offsets(sizes []int) []int {
+	starts := make([]int, len(sizes)+1)
+	for i, s := range sizes {
+		starts[i+1] = starts[i] + s
+	}
+	return starts
+}
+
+// Compose draws each view into its rect on a width x height screen. Views
+// are clipped and padded to their rect; areas no rect covers stay blank.
+func Compose(width, height int, rects []Rect, views []string) string {
+	type block struct {
+		rect  Rect
+		lines []string
+	}
+	var blocks []block
+	for i, r := range rects {
+		if r.Empty() || i >= len(views) {
+			continue
+		}
+		blocks = append(blocks, block{r, strings.Split(views[i], "\n")})
+	}
+	sort.Slice(blocks, func(a, b int) bool { return blocks[a].rect.X < blocks[b].rect.X })
+
+	clip := lipgloss.NewStyle()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		var sb strings.Builder
+		x := 0
+		for _, b := range blocks {
+			if y < b.rect.Y || y >= b.rect.Y+b.rect.H {
+				continue
+			}
+			if b.rect.X > x {
+				sb.WriteString(strings.Repeat(" ", b.rect.X-x))
+			}
+			line := ""
+			if row := y - b.rect.Y; row < len(b.lines) {
+				line = clip.MaxWidth(b.rect.W).Render(b.lines[row])
+			}
+			sb.WriteString(line)
+			if pad := b.rect.W - lipgloss.Width(line); pad > 0 {
+				sb.WriteString(strings.Repeat(" ", pad))
+			}
+			x = b.rect.X + b.rect.W
+		}
+		if x < width {
+			sb.WriteString(strings.Repeat(" ", width-x))
+		}
+		lines[y] = sb.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,155 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		tracks []Track
+		want   []int
+	}{
+		{"even", 90, []Track{{}, {}, {}}, []int{30, 30, 30}},
+		{"remainder to first", 100, []Track{{}, {}, {}}, []int{34, 33, 33}},
+		{"remainder by largest share", 10, []Track{{Weight: 1}, {Weight: 2}}, []int{3, 7}},
+		{"weights below 1 count as 1", 10, []Track{{Weight: -3}, {Weight: 0}}, []int{5, 5}},
+		{"fixed then weighted", 50, []Track{{Size: 10}, {Weight: 1}, {Weight: 3}}, []int{10, 10, 30}},
+		{"fixed shrinks to fit", 12, []Track{{Size: 8}, {Size: 8}, {}}, []int{8, 4, 0}},
+		{"only fixed, last absorbs slack", 30, []Track{{Size: 5}, {Size: 5}}, []int{5, 25}},
+		{"no space", 0, []Track{{}, {}}, []int{0, 0}},
+		{"no tracks", 40, nil, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distribute(tt.total, tt.tracks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distribute(%d, %v) = %v, want %v", tt.total, tt.tracks, got, tt.want)
+			}
+		})
+	}
+}
+
+// Whatever the tracks, the sizes fill the space exactly
+func TestDistributeFillsTotal(t *testing.T) {
+	grids := [][]Track{
+		{{}, {}, {}},
+		{{Weight: 1}, {Weight: 2}, {Weight: 4}},
+		{{Size: 3}, {}, {Weight: 5}, {Size: 7}},
+		{{Size: 20}, {Size: 20}},
+		{{Weight: 7}},
+	}
+	for _, tracks := range grids {
+		for total := 1; total <= 200; total++ {
+			sum := 0
+			for _, s := range Distribute(total, tracks) {
+				if s < 0 {
+					t.Fatalf("Distribute(%d, %v) has a negative size", total, tracks)
+				}
+				sum += s
+			}
+			if sum != total {
+				t.Fatalf("Distribute(%d, %v) sums to %d", total, tracks, sum)
+			}
+		}
+	}
+}
+
+func TestPlace(t *testing.T) {
+	grid := Grid{Rows: []Track{{}, {}}, Cols: []Track{{}, {}, {}}}
+	auto := Cell{Row: -1, Col: -1}
+	tests := []struct {
+		name  string
+		cells []Cell
+		want  []Rect
+	}{
+		{
+			"reading order",
+			[]Cell{auto, auto, auto, auto},
+			[]Rect{{0, 0, 30, 10}, {30, 0, 30, 10}, {60, 0, 30, 10}, {0, 10, 30, 10}},
+		},
+		{
+			"explicit first, auto fills around",
+			[]Cell{auto, {Row: 0, Col: 0, ColSpan: 2}, auto},
+			[]Rect{{60, 0, 30, 10}, {0, 0, 60, 10}, {0, 10, 30, 10}},
+		},
+		{
+			"spans clipped at the edge",
+			[]Cell{{Row: 1, Col: 2, RowSpan: 3, ColSpan: 2}},
+			[]Rect{{60, 10, 30, 10}},
+		},
+		{
+			"auto span wider than the grid",
+			[]Cell{{Row: -1, Col: -1, ColSpan: 5}},
+			[]Rect{{0, 0, 90, 10}},
+		},
+		{
+			"overlap and overflow get nothing",
+			[]Cell{{Row: 0, Col: 0, RowSpan: 2, ColSpan: 3}, {Row: 1, Col: 1}, auto, {Row: 4, Col: 0}},
+			[]Rect{{0, 0, 90, 20}, {}, {}, {}},
+		},
+		{
+			"auto skips spots its span doesn't fit",
+			[]Cell{{Row: 0, Col: 1}, {Row: -1, Col: -1, ColSpan: 2}},
+			[]Rect{{30, 0, 30, 10}, {0, 10, 60, 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grid.Place(90, 20, tt.cells); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Place(%v) = %v, want %v", tt.cells, got, tt.want)
+			}
+		})
+	}
+}
+
+// A full grid covers every cell of the screen exactly once
+func TestPlaceTilesScreen(t *testing.T) {
+	grid := Grid{
+		Rows: []Track{{Size: 3}, {Weight: 2}, {}},
+		Cols: []Track{{}, {Weight: 3}, {Size: 11}, {}},
+	}
+	var cells []Cell
+	for range 12 {
+		cells = append(cells, Cell{Row: -1, Col: -1})
+	}
+	for _, size := range [][2]int{{80, 24}, {123, 41}, {17, 9}} {
+		width, height := size[0], size[1]
+		covered := make([]int, width*height)
+		for _, r := range grid.Place(width, height, cells) {
+			for y := r.Y; y < r.Y+r.H; y++ {
+				for x := r.X; x < r.X+r.W; x++ {
+					covered[y*width+x]++
+				}
+			}
+		}
+		for i, n := range covered {
+			if n != 1 {
+				t.Fatalf("%dx%d: cell %d,%d covered %d times", width, height, i%width, i/width, n)
+			}
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	rects := []Rect{{0, 0, 4, 2}, {6, 1, 3, 2}, {}}
+	views := []string{"abcdef\nxy", "123\n456\n789", "hidden"}
+	got := Compose(10, 3, rects, views)
+	want := strings.Join([]string{
+		"abcd      ",
+		"xy    123 ",
+		"      456 ",
+	}, "\n")
+	if got != want {
+		t.Errorf("Compose() =\n%s\nwant\n%s", got, want)
+	}
+	for i, line := range strings.Split(got, "\n") {
+		if w := lipgloss.Width(line); w != 10 {
+			t.Errorf("line %d is %d cells wide, want 10", i, w)
+		}
+	}
+}
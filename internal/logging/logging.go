@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cj3636/gotui/internal/secret"
+)
+
+// WidgetKey is the attribute naming the widget a record is about
+const WidgetKey = "widget"
+
+// historySize is how many records the history keeps
+const historySize = 500
+
+// Entry is one record kept in the history
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Widget  string
+	Message string
+}
+
+// Levels are the level names --log-level and the log widget accept
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel returns the level with the given name
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (known levels: %s)", name, strings.Join(Levels, ", "))
+	}
+	return level, nil
+}
+
+// Setup sends the log/slog and log packages' output to the history and, when
+// w isn't nil, to w as text at the given level and above. Secrets are
+// redacted from both.
+func Setup(w io.Writer, level slog.Level) {
+	handlers := []slog.Handler{&historyHandler{}}
+	if w != nil {
+		handlers = append(handlers, slog.NewTextHandler(secret.Writer(w), &slog.HandlerOptions{Level: level}))
+	}
+	slog.SetDefault(slog.New(fanout(handlers)))
+}
+
+// OpenFile opens a log file for appending, readable only by the user
+func OpenFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+}
+
+var (
+	mu      sync.Mutex
+	history []Entry
+	next    int
+	count   uint64
+)
+
+// Recent returns the kept records at level and above, newest first, and a
+// count of records kept so far that changes whenever a record is added
+func Recent(level slog.Level) ([]Entry, uint64) {
+	mu.Lock()
+	defer mu.Unlock()
+	var entries []Entry
+	for i := range history {
+		e := history[(next-1-i+len(history))%len(history)]
+		if e.Level >= level {
+			entries = append(entries, e)
+		}
+	}
+	return entries, count
+}
+
+func record(e Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(history) < historySize {
+		history = append(history, e)
+	} else {
+		history[next] = e
+	}
+	next = (next + 1) % historySize
+	count++
+}
+
+// historyHandler keeps info records and above for the log widget
+type historyHandler struct {
+	attrs []slog.Attr
+}
+
+func (h *historyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *historyHandler) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{Time: r.Time, Level: r.Level}
+	var msg strings.Builder
+	msg.WriteString(r.Message)
+	add := func(a slog.Attr) bool {
+		switch a.Key {
+		case WidgetKey:
+			e.Widget = a.Value.String()
+		case "err":
+			fmt.Fprintf(&msg, ": %s", a.Value)
+		default:
+			fmt.Fprintf(&msg, " %s=%s", a.Key, a.Value)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(add)
+	e.Message = secret.Redact(msg.String())
+	record(e)
+	return nil
+}
+
+func (h *historyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &historyHandler{attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+// WithGroup keeps the history flat; nothing logs with groups
+func (h *historyHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// fanout passes each record to every handler that takes its level
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reference prefixes. A value without one of them is the secret itself.
+const (
+	EnvPrefix  = "env:"
+	FilePrefix = "file:"
+	CmdPrefix  = "cmd:"
+)
+
+// cmdTimeout bounds how long a cmd: reference may take
+const cmdTimeout = 10 * time.Second
+
+// Placeholder replaces secrets in redacted text
+const Placeholder = "[redacted]"
+
+// refs lists the reference prefixes and what follows each
+var refs = []struct {
+	prefix string
+	what   string
+}{
+	{EnvPrefix, "variable name"},
+	{FilePrefix, "file path"},
+	{CmdPrefix, "command"},
+}
+
+// IsRef reports whether value refers to a secret kept elsewhere
+func IsRef(value string) bool {
+	for _, r := range refs {
+		if strings.HasPrefix(value, r.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check rejects references that name nothing to look up
+func Check(value string) error {
+	for _, r := range refs {
+		if rest, ok := strings.CutPrefix(value, r.prefix); ok && strings.TrimSpace(rest) == "" {
+			return fmt.Errorf("%q needs a %s after the colon", value, r.what)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the secret value refers to: an environment variable, the
+// contents of a file only its owner can read, or the output of a command,
+// with surrounding whitespace trimmed. Values that aren't references are
+// returned as they are. Every secret returned, plaintext or not, is
+// remembered for Redact. Errors never contain the secret.
+func Resolve(value string) (string, error) {
+	if err := Check(value); err != nil {
+		return "", err
+	}
+
+	var secret string
+	var err error
+	switch {
+	case strings.HasPrefix(value, EnvPrefix):
+		secret, err = fromEnv(strings.TrimPrefix(value, EnvPrefix))
+	case strings.HasPrefix(value, FilePrefix):
+		secret, err = fromFile(strings.TrimPrefix(value, FilePrefix))
+	case strings.HasPrefix(value, CmdPrefix):
+		secret, err = fromCmd(strings.TrimPrefix(value, CmdPrefix))
+	default:
+		secret = value
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", value, Redact(err.Error()))
+	}
+	if secret == "" && IsRef(value) {
+		return "", fmt.Errorf("%s is empty", value)
+	}
+	remember(secret)
+	return secret, nil
+}
+
+func fromEnv(name string) (string, error) {
+	v, ok := os.LookupEnv(strings.TrimSpace(name))
+	if !ok {
+		return "", fmt.Errorf("variable %s is not set", name)
+	}
+	return strings.TrimSpace(v), nil
+}
+
+func fromFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	// Windows permissions don't map to mode bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s can be read by other users (mode %#o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func fromCmd(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("command took longer than %s", cmdTimeout)
+	}
+	if err != nil {
+		// The first line of stderr usually says what went wrong
+		if first, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); first != "" {
+			return "", fmt.Errorf("%v: %s", err, first)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+var (
+	mu    sync.RWMutex
+	known []string
+)
+
+// remember adds a secret to those Redact hides, longest first so a secret
+// containing another is hidden whole
+func remember(secret string) {
+	// Very short values would hide unrelated text
+	if len(secret) < 4 {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range known {
+		if s == secret {
+			return
+		}
+	}
+	known = append(known, secret)
+	sort.Slice(known, func(i, j int) bool { return len(known[i]) > len(known[j]) })
+}
+
+// Redact replaces every secret resolved so far in text with Placeholder
+func Redact(text string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, s := range known {
+		text = strings.ReplaceAll(text, s, Placeholder)
+	}
+	return text
+}
+
+// Writer redacts secrets from everything written to w. Each write is
+// redacted on its own, which suits line-at-a-time writers like the log
+// package.
+func Writer(w io.Writer) io.Writer {
+	return redactWriter{w}
+}
+
+type redactWriter struct {
+	w io.Writer
+}
+
+func (r redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,163 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain-token", ""},
+		{"env:GOTUI_TOKEN", ""},
+		{"env:", `"env:" needs a variable name after the colon`},
+		{"file:  ", `"file:  " needs a file path after the colon`},
+		{"cmd:", `"cmd:" needs a command after the colon`},
+	}
+	for _, tt := range tests {
+		err := Check(tt.value)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("Check(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GOTUI_TEST_TOKEN", "  env-secret\n")
+	t.Setenv("GOTUI_TEST_EMPTY", "")
+
+	dir := t.TempDir()
+	private := filepath.Join(dir, "private")
+	if err := os.WriteFile(private, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{value: "plain-secret", want: "plain-secret"},
+		{value: "env:GOTUI_TEST_TOKEN", want: "env-secret"},
+		{value: "env:GOTUI_TEST_UNSET", wantErr: "env:GOTUI_TEST_UNSET: variable GOTUI_TEST_UNSET is not set"},
+		{value: "env:GOTUI_TEST_EMPTY", wantErr: "env:GOTUI_TEST_EMPTY is empty"},
+		{value: "file:" + private, want: "file-secret"},
+		{value: "env:", wantErr: `"env:" needs a variable name after the colon`},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests, struct {
+			value   string
+			want    string
+			wantErr string
+		}{value: "cmd:echo cmd-secret", want: "cmd-secret"})
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestFromFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows permissions don't map to mode bits")
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		mode    os.FileMode
+		wantErr bool
+	}{
+		{0o600, false},
+		{0o400, false},
+		{0o640, true},
+		{0o644, true},
+		{0o604, true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.mode.String())
+		if err := os.WriteFile(path, []byte("mode-secret"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// WriteFile's mode is subject to the umask
+		if err := os.Chmod(path, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		_, err := fromFile(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("fromFile with mode %#o: error = %v, want error %v", tt.mode, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "chmod 600") {
+			t.Errorf("fromFile with mode %#o: error %q doesn't say how to fix it", tt.mode, err)
+		}
+	}
+}
+
+func TestResolveErrorHidesSecret(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	t.Setenv("GOTUI_TEST_LEAK", "hunter2-leaked")
+	if _, err := Resolve("hunter2-leaked"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Resolve(`cmd:echo "$GOTUI_TEST_LEAK" >&2; exit 1`)
+	if err == nil {
+		t.Fatal("a failing command resolved")
+	}
+	if msg := err.Error(); strings.Contains(msg, "hunter2-leaked") || !strings.Contains(msg, Placeholder) {
+		t.Errorf("error shows the secret from stderr: %q", msg)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	for _, s := range []string{"abc", "sk-short", "sk-short-and-then-longer"} {
+		remember(s)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"nothing secret", "nothing secret"},
+		{"abc is too short to hide", "abc is too short to hide"},
+		{"token sk-short here", "token [redacted] here"},
+		{"token sk-short-and-then-longer here", "token [redacted] here"},
+		{"sk-short sk-short", "[redacted] [redacted]"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.text); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	remember("writer-secret")
+
+	var b strings.Builder
+	w := Writer(&b)
+	line := "auth failed for writer-secret\n"
+	n, err := w.Write([]byte(line))
+	if err != nil || n != len(line) {
+		t.Errorf("Write() = %d, %v, want %d, nil", n, err, len(line))
+	}
+	if got, want := b.String(), "auth failed for [redacted]\n"; got != want {
+		t.Errorf("Writer wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a palette and border style. Colors are ANSI 256-color numbers or
+// hex values; an empty Text color keeps the terminal's own.
+type Theme struct {
+	Border      lipgloss.Border
+	BorderColor lipgloss.Color
+	// Focus colors the focused panel's border and the current page's tab
+	Focus lipgloss.Color
+	Title lipgloss.Color
+	Text  lipgloss.Color
+	// Muted colors the help line, inactive tabs and scroll positions
+	Muted   lipgloss.Color
+	Error   lipgloss.Color
+	Success lipgloss.Color
+}
+
+// DefaultName is the theme used when the config doesn't pick one
+const DefaultName = "dark"
+
+var builtin = map[string]Theme{
+	"dark": {
+		Border:      lipgloss.RoundedBorder(),
+		BorderColor: "63",
+		Focus:       "205",
+		Title:       "170",
+		Muted:       "241",
+		Error:       "196",
+		Success:     "42",
+	},
+	"light": {
+		Border:      lipgloss.RoundedBorder(),
+		BorderColor: "25",
+		Focus:       "162",
+		Title:       "91",
+		Text:        "235",
+		Muted:       "244",
+		Error:       "160",
+		Success:     "28",
+	},
+	"solarized": {
+		Border:      lipgloss.RoundedBorder(),
+		BorderColor: "#268bd2",
+		Focus:       "#d33682",
+		Title:       "#b58900",
+		Text:        "#839496",
+		Muted:       "#586e75",
+		Error:       "#dc322f",
+		Success:     "#859900",
+	},
+	"high-contrast": {
+		Border:      lipgloss.ThickBorder(),
+		BorderColor: "15",
+		Focus:       "11",
+		Title:       "14",
+		Text:        "15",
+		Muted:       "250",
+		Error:       "9",
+		Success:     "10",
+	},
+}
+
+// Default returns the default theme
+func Default() Theme {
+	return builtin[DefaultName]
+}
+
+// Named returns the built-in theme with the given name
+func Named(name string) (Theme, bool) {
+	t, ok := builtin[name]
+	return t, ok
+}
+
+// Names returns the built-in theme names in sorted order
+func Names() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// borders are the border styles a theme can use, by name
+var borders = map[string]lipgloss.Border{
+	"rounded": lipgloss.RoundedBorder(),
+	"normal":  lipgloss.NormalBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+// BorderNamed returns the border style with the given name
+func BorderNamed(name string) (lipgloss.Border, bool) {
+	b, ok := borders[name]
+	return b, ok
+}
+
+// BorderNames returns the border style names in sorted order
+func BorderNames() []string {
+	names := make([]string, 0, len(borders))
+	for name := range borders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colors maps the names a config uses for theme colors to their fields, in
+// the order they are listed
+var colors = []struct {
+	name  string
+	field func(*Theme) *lipgloss.Color
+}{
+	{"border", func(t *Theme) *lipgloss.Color { return &t.BorderColor }},
+	{"focus", func(t *Theme) *lipgloss.Color { return &t.Focus }},
+	{"title", func(t *Theme) *lipgloss.Color { return &t.Title }},
+	{"text", func(t *Theme) *lipgloss.Color { return &t.Text }},
+	{"muted", func(t *Theme) *lipgloss.Color { return &t.Muted }},
+	{"error", func(t *Theme) *lipgloss.Color { return &t.Error }},
+	{"success", func(t *Theme) *lipgloss.Color { return &t.Success }},
+}
+
+// ColorNames returns the names of the theme's colors
+func ColorNames() []string {
+	names := make([]string, len(colors))
+	for i, c := range colors {
+		names[i] = c.name
+	}
+	return names
+}
+
+// SetColor replaces the named color, reporting whether the name exists
+func (t *Theme) SetColor(name string, value lipgloss.Color) bool {
+	for _, c := range colors {
+		if c.name == name {
+			*c.field(t) = value
+			return true
+		}
+	}
+	return false
+}
+
+var hexColorRE = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// CheckColor rejects values that are neither a 256-color number nor a hex
+// color
+func CheckColor(value string) error {
+	if hexColorRE.MatchString(value) {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("%q is not a color number from 0 to 255 or a #rrggbb value", value)
+}
@@ -1,17 +1,19 @@
 package main
 
 import (
-	"log"
+	_ "embed"
+	"os"
+)
 
-	tea "github.com/charmbracelet/bubbletea"
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
 
-	"gotui/widgets"
-)
+// exampleConfig is config.example.yaml, the basis of the file "gotui config
+// init" writes
+//
+//go:embed config.example.yaml
+var exampleConfig []byte
 
 func main() {
-	model := widgets.NewDashboard()
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if err := p.Start(); err != nil {
-		log.Fatalf("failed to start program: %v", err)
-	}
+	os.Exit(run(os.Args[1:]))
 }
@@ -0,0 +1,94 @@
+package widgets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachedPayload is a widget's last successful fetch as stored on disk
+type cachedPayload struct {
+	Saved time.Time       `json:"saved"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// cacheDir returns $XDG_CACHE_HOME/gotui, or ~/.cache/gotui, or "" when
+// neither can be found, which turns the cache off. Tests replace it to keep
+// away from the user's cache.
+var cacheDir = sync.OnceValue(func() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "gotui")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".cache", "gotui")
+	}
+	return ""
+})
+
+// cacheKey names the cache file of a widget. The settings that decide what
+// it fetches are part of the key, so editing them doesn't show the old data.
+func cacheKey(kind, id string, settings ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{id}, settings...), "\x00")))
+	return kind + "-" + hex.EncodeToString(sum[:8])
+}
+
+// loadCached decodes the payload last saved under key into v and returns
+// when it was saved. It reports false when there is none or it can't be read.
+func loadCached(key string, v any) (time.Time, bool) {
+	dir := cacheDir()
+	if dir == "" {
+		return time.Time{}, false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var p cachedPayload
+	if err := json.Unmarshal(data, &p); err != nil || json.Unmarshal(p.Data, v) != nil {
+		return time.Time{}, false
+	}
+	return p.Saved, true
+}
+
+// saveCached stores v under key. The cache is only an aid to startup, so
+// failures are ignored. Payloads can describe private repositories, so
+// only the user can read them.
+func saveCached(key string, v any) {
+	dir := cacheDir()
+	if dir == "" {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err = json.Marshal(cachedPayload{Saved: time.Now(), Data: data})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+
+	// Write then rename so a reader never sees half a file
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, key+".json")); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,36 @@
+package widgets
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gotui")
+	useCacheDir(t, dir)
+
+	key := cacheKey("ip", "ip")
+	if _, ok := loadCached(key, &IPInfo{}); ok {
+		t.Fatal("loaded a payload from an empty cache")
+	}
+	saveCached(key, IPInfo{IP: "192.0.2.7"})
+
+	info, err := os.Stat(filepath.Join(dir, key+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		t.Errorf("cache file has mode %#o, want it readable by its owner only", perm)
+	}
+
+	// A new widget starts from the saved payload
+	w := NewIPWidget("ip", 60)
+	if w.ipInfo.IP != "192.0.2.7" || !w.HasData() {
+		t.Errorf("new widget has %+v, want the cached address", w.ipInfo)
+	}
+	if other := NewIPWidget("ip-2", 60); other.HasData() {
+		t.Error("a widget with another id loaded the payload")
+	}
+}
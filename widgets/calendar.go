@@ -12,31 +12,45 @@ import (
 type CalendarWidget struct {
 	BaseWidget
 	currentTime time.Time
+	schedule    Schedule
+}
+
+func init() {
+	Register(Type{
+		Name:        "calendar",
+		Description: "Current month with today highlighted",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewCalendarWidget(spec.ID), nil
+		},
+		IgnoresRefresh: true,
+	})
 }
 
 // NewCalendarWidget creates a new calendar widget
-func NewCalendarWidget() *CalendarWidget {
+func NewCalendarWidget(id string) *CalendarWidget {
 	return &CalendarWidget{
-		BaseWidget:  NewBaseWidget("📅 Calendar"),
+		BaseWidget:  NewBaseWidget(id, "📅 Calendar"),
 		currentTime: time.Now(),
+		schedule:    NewSchedule(id),
 	}
 }
 
 // Init initializes the widget
 func (w *CalendarWidget) Init() tea.Cmd {
-	return tea.Tick(time.Minute, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+	// The current time is all there is to show
+	w.MarkLoaded()
+	return w.schedule.After(time.Minute)
 }
 
 // Update handles messages
 func (w *CalendarWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
 	switch msg := msg.(type) {
-	case TickMsg:
-		w.currentTime = time.Time(msg)
-		return w, tea.Tick(time.Minute, func(t time.Time) tea.Msg {
-			return TickMsg(t)
-		})
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		w.currentTime = msg.Time
+		return w, w.schedule.After(time.Minute)
 	}
 	return w, nil
 }
@@ -1,34 +1,66 @@
 package widgets
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// clockWidget displays the current time.
-type clockWidget struct {
-	now time.Time
+// ClockWidget displays the current time
+type ClockWidget struct {
+	BaseWidget
+	currentTime time.Time
+	schedule    Schedule
 }
 
-// NewClockWidget returns an initialized clock widget.
-func NewClockWidget() Widget {
-	return &clockWidget{now: time.Now()}
+func init() {
+	Register(Type{
+		Name:        "clock",
+		Description: "Current date and time, updated every second",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewClockWidget(spec.ID), nil
+		},
+		IgnoresRefresh: true,
+	})
 }
 
-func (c *clockWidget) Title() string { return "Clock" }
+// NewClockWidget creates a new clock widget
+func NewClockWidget(id string) *ClockWidget {
+	return &ClockWidget{
+		BaseWidget:  NewBaseWidget(id, "🕐 Clock"),
+		currentTime: time.Now(),
+		schedule:    NewSchedule(id),
+	}
+}
 
-func (c *clockWidget) Init() tea.Cmd { return Tick(time.Second) }
+// Init initializes the widget
+func (w *ClockWidget) Init() tea.Cmd {
+	// The current time is all there is to show
+	w.MarkLoaded()
+	return w.schedule.After(time.Second)
+}
 
-func (c *clockWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch msg.(type) {
-	case TickMsg:
-		c.now = time.Now()
-		return c, Tick(time.Second)
+// Update handles messages
+func (w *ClockWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		w.currentTime = msg.Time
+		return w, w.schedule.After(time.Second)
 	}
-	return c, nil
+	return w, nil
 }
 
-func (c *clockWidget) View(width, _ int) string {
-	return c.now.Format("Mon Jan _2 15:04:05 MST")
+// View renders the widget
+func (w *ClockWidget) View() string {
+	content := fmt.Sprintf(
+		"%s\n\n%s\n%s",
+		w.currentTime.Format("Monday"),
+		w.currentTime.Format("January 2, 2006"),
+		w.currentTime.Format("15:04:05"),
+	)
+	return w.RenderContent(content)
 }
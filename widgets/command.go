@@ -0,0 +1,50 @@
+package widgets
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Command is an action offered in the command palette
+type Command struct {
+	// Title is what the palette lists and matches the query against
+	Title string
+	Run   func() tea.Cmd
+}
+
+// Commander is implemented by widgets that offer commands of their own in
+// the command palette
+type Commander interface {
+	Commands() []Command
+}
+
+// StatusMsg asks the dashboard to show text in its status line
+type StatusMsg struct {
+	Text string
+	Err  bool
+}
+
+// OpenURL returns a command that opens url in the default browser and
+// reports the outcome in the status line
+func OpenURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			return StatusMsg{Text: fmt.Sprintf("Couldn't open %s: %v", url, err), Err: true}
+		}
+		// Don't leave a zombie behind once the opener exits
+		go cmd.Wait()
+		return StatusMsg{Text: "Opened " + url}
+	}
+}
@@ -1,90 +1,341 @@
 package widgets
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/httpclient"
+	"github.com/google/go-github/v57/github"
+	"golang.org/x/oauth2"
 )
 
-type githubUser struct {
-	Login       string `json:"login"`
-	Name        string `json:"name"`
-	PublicRepos int    `json:"public_repos"`
-	Followers   int    `json:"followers"`
+// GithubWidget displays GitHub repository information
+type GithubWidget struct {
+	BaseWidget
+	client         *github.Client
+	cacheKey       string
+	repos          []string
+	repoInfo       []RepoInfo
+	err            error
+	updateInterval time.Duration
+	schedule       Schedule
+	scroll         ScrollView
+	rate           github.Rate
+	// holdUntil is when GitHub's secondary rate limit lets requests
+	// through again
+	holdUntil time.Time
 }
 
-type githubWidget struct {
-	user    githubUser
-	message string
-	err     error
+// RepoInfo contains repository information
+type RepoInfo struct {
+	Name        string
+	Stars       int
+	Forks       int
+	OpenIssues  int
+	OpenPRs     int
+	Description string
 }
 
-// NewGitHubWidget constructs the GitHub widget.
-func NewGitHubWidget() Widget { return &githubWidget{} }
+// GithubMsg contains GitHub information
+type GithubMsg struct {
+	id    string
+	repos []RepoInfo
+	err   error
+	// rate is the quota left after the fetch, if GitHub said
+	rate github.Rate
+	// retryAfter is how long GitHub asked to wait after a secondary rate
+	// limit
+	retryAfter time.Duration
+}
 
-func (g *githubWidget) Title() string { return "GitHub" }
+// WidgetID implements Addressed
+func (m GithubMsg) WidgetID() string { return m.id }
 
-func (g *githubWidget) Init() tea.Cmd { return g.fetch() }
+func init() {
+	Register(Type{
+		Name:        "github",
+		Description: "Stars, forks, open issues and pull requests of GitHub repositories",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewGithubWidget(
+				spec.ID,
+				spec.Options.String("token", ""),
+				spec.Options.Strings("repos"),
+				spec.Refresh,
+			), nil
+		},
+		Options: []Option{
+			{Name: "token", Kind: StringOption, Help: "API token or env:, file: or cmd: reference (default github_token)", Secret: true},
+			{Name: "repos", Kind: StringListOption, Help: "owner/repo names (default github_repos)", Check: checkRepoName},
+		},
+	})
+}
 
-func (g *githubWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch msg := msg.(type) {
-	case TickMsg:
-		return g, g.fetch()
-	case githubMsg:
-		g.user = msg.user
-		g.message = msg.message
-		g.err = msg.err
-		return g, Tick(10 * time.Minute)
+// splitRepo splits an "owner/repo" name
+func splitRepo(name string) (owner, repo string, ok bool) {
+	owner, repo, ok = strings.Cut(name, "/")
+	if !ok || owner == "" || repo == "" || strings.ContainsAny(repo, "/ ") || strings.Contains(owner, " ") {
+		return "", "", false
 	}
-	return g, nil
+	return owner, repo, true
 }
 
-func (g *githubWidget) View(width, _ int) string {
-	if g.err != nil {
-		return fmt.Sprintf("Error: %v", g.err)
+func checkRepoName(name string) error {
+	if _, _, ok := splitRepo(name); !ok {
+		return fmt.Errorf("%q is not an owner/repo name", name)
+	}
+	return nil
+}
+
+// NewGithubWidget creates a new GitHub widget
+func NewGithubWidget(id, token string, repos []string, refreshInterval int) *GithubWidget {
+	// The client is kept across refreshes so connections are reused
+	httpClient := httpclient.Client()
+	if token != "" {
+		httpClient = &http.Client{
+			Transport: &oauth2.Transport{
+				Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+				Base:   httpClient.Transport,
+			},
+			Timeout: httpClient.Timeout,
+		}
 	}
-	if g.user.Login == "" && g.message == "" {
-		return "Loading profile..."
+	w := &GithubWidget{
+		BaseWidget:     NewBaseWidget(id, "🐙 GitHub"),
+		client:         github.NewClient(httpClient),
+		cacheKey:       cacheKey("github", id, repos...),
+		repos:          repos,
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+		scroll:         NewScrollView(),
 	}
-	if g.user.Login == "" {
-		return g.message
+	// Show the last known data until the first fetch finishes
+	if saved, ok := loadCached(w.cacheKey, &w.repoInfo); ok {
+		w.scroll.SetContent(w.formatRepos())
+		w.MarkCached(saved)
 	}
-	return fmt.Sprintf("User: %s\nName: %s\nRepos: %d\nFollowers: %d", g.user.Login, g.user.Name, g.user.PublicRepos, g.user.Followers)
+	return w
 }
 
-type githubMsg struct {
-	user    githubUser
-	message string
-	err     error
+// Init initializes the widget
+func (w *GithubWidget) Init() tea.Cmd {
+	return w.fetchGithubInfo()
 }
 
-func (g *githubWidget) fetch() tea.Cmd {
-	return func() tea.Msg {
-		token := os.Getenv("GITHUB_TOKEN")
-		client := &http.Client{Timeout: 5 * time.Second}
-		req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
-		if token != "" {
-			req.Header.Set("Authorization", "token "+token)
+// Update handles messages
+func (w *GithubWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case GithubMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.rate.Limit > 0 {
+			w.rate = msg.rate
+		}
+		w.holdUntil = time.Time{}
+		if msg.retryAfter > 0 {
+			w.holdUntil = time.Now().Add(msg.retryAfter)
 		}
-		resp, err := client.Do(req)
-		if err != nil {
-			return githubMsg{err: err}
+		switch {
+		case msg.err == nil:
+			w.repoInfo = msg.repos
+			w.scroll.SetContent(w.formatRepos())
+			w.err = nil
+			w.MarkUpdated()
+		case isRateLimited(msg.err) && w.HasData():
+			// Keep showing the last data; the footer already says when
+			// refreshing resumes
+			w.fetchErr = nil
+		case w.HasData():
+			// Keep showing the last data; the footer shows the error
+		default:
+			w.err = msg.err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusUnauthorized {
-			return githubMsg{message: "Set GITHUB_TOKEN to load private data"}
+		delay := w.nextRefresh()
+		w.SetStatus(w.rateStatus(delay))
+		return w, w.schedule.After(delay)
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.fetchGithubInfo()
+	case tea.KeyMsg, tea.MouseMsg:
+		return w, w.scroll.Update(msg)
+	}
+	return w, nil
+}
+
+// isRateLimited reports whether err is GitHub refusing a request because a
+// rate limit was reached
+func isRateLimited(err error) bool {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateErr) || errors.As(err, &abuseErr)
+}
+
+// requestsPerRefresh is how many API requests one refresh makes at most.
+// Unchanged resources answered from the cache don't count, so it errs high.
+func (w *GithubWidget) requestsPerRefresh() int {
+	return max(2*len(w.repos), 1)
+}
+
+// nextRefresh returns how long to wait before the next fetch. The quota is
+// spread over the time until it resets, stretching the interval when
+// refreshing as configured would run out first, and fetching waits for the
+// reset once too little is left for a whole refresh.
+func (w *GithubWidget) nextRefresh() time.Duration {
+	now := time.Now()
+	if w.holdUntil.After(now) {
+		return max(w.updateInterval, w.holdUntil.Sub(now))
+	}
+	if w.rate.Limit == 0 || !w.rate.Reset.After(now) {
+		return w.updateInterval
+	}
+
+	// A little past the reset, so GitHub's clock has moved on too
+	untilReset := w.rate.Reset.Sub(now) + 5*time.Second
+	refreshes := w.rate.Remaining / w.requestsPerRefresh()
+	if refreshes == 0 {
+		return untilReset
+	}
+	return max(w.updateInterval, untilReset/time.Duration(refreshes))
+}
+
+// rateStatus describes the quota left for the footer, and when the next
+// refresh is if the quota moved it from the configured interval
+func (w *GithubWidget) rateStatus(delay time.Duration) string {
+	now := time.Now()
+	switch {
+	case w.holdUntil.After(now):
+		return "API limited, resumes " + w.holdUntil.Format("15:04")
+	case w.rate.Limit == 0:
+		return ""
+	case w.rate.Remaining < w.requestsPerRefresh() && w.rate.Reset.After(now):
+		return fmt.Sprintf("API %d/%d, resumes %s", w.rate.Remaining, w.rate.Limit, w.rate.Reset.Format("15:04"))
+	case delay > w.updateInterval:
+		return fmt.Sprintf("API %d/%d, every %s", w.rate.Remaining, w.rate.Limit, formatInterval(delay))
+	default:
+		return fmt.Sprintf("API %d/%d", w.rate.Remaining, w.rate.Limit)
+	}
+}
+
+// formatInterval rounds d for display
+func formatInterval(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
+// KeyBindings implements KeyHelper
+func (w *GithubWidget) KeyBindings() []key.Binding {
+	return w.scroll.KeyBindings()
+}
+
+// Commands implements Commander, offering to open each repository
+func (w *GithubWidget) Commands() []Command {
+	var cmds []Command
+	for _, name := range w.repos {
+		if _, _, ok := splitRepo(name); !ok {
+			continue
 		}
-		if resp.StatusCode != http.StatusOK {
-			return githubMsg{message: fmt.Sprintf("API status: %s", resp.Status)}
+		url := "https://github.com/" + name
+		cmds = append(cmds, Command{
+			Title: fmt.Sprintf("Open %s on GitHub", name),
+			Run:   func() tea.Cmd { return OpenURL(url) },
+		})
+	}
+	return cmds
+}
+
+// View renders the widget
+func (w *GithubWidget) View() string {
+	var content string
+	if w.err != nil {
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if len(w.repoInfo) == 0 {
+		if len(w.repos) == 0 {
+			content = "No repositories configured"
+		} else {
+			content = "Loading..."
 		}
-		var user githubUser
-		if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-			return githubMsg{err: err}
+	} else {
+		content = w.RenderScroll(&w.scroll)
+	}
+	return w.RenderContent(content)
+}
+
+// formatRepos renders one entry per repository
+func (w *GithubWidget) formatRepos() string {
+	var lines []string
+	for _, repo := range w.repoInfo {
+		lines = append(lines, fmt.Sprintf(
+			"%s\n⭐ %d  🍴 %d  📝 %d",
+			repo.Name,
+			repo.Stars,
+			repo.Forks,
+			repo.OpenIssues,
+		))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func (w *GithubWidget) fetchGithubInfo() tea.Cmd {
+	id, client, key := w.id, w.client, w.cacheKey
+	return func() tea.Msg {
+		ctx, cancel := httpclient.Context()
+		defer cancel()
+
+		var repos []RepoInfo
+		var rate github.Rate
+		for _, repoName := range w.repos {
+			owner, repo, ok := splitRepo(repoName)
+			if !ok {
+				return GithubMsg{id: id, err: checkRepoName(repoName)}
+			}
+
+			repoData, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return rateLimitMsg(id, err)
+			}
+			rate = resp.Rate
+
+			// Get pull requests count
+			prs, resp, _ := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+				State: "open",
+			})
+			if resp != nil && resp.Rate.Limit > 0 {
+				rate = resp.Rate
+			}
+
+			info := RepoInfo{
+				Name:       repoName,
+				Stars:      repoData.GetStargazersCount(),
+				Forks:      repoData.GetForksCount(),
+				OpenIssues: repoData.GetOpenIssuesCount(),
+				OpenPRs:    len(prs),
+			}
+			repos = append(repos, info)
 		}
-		return githubMsg{user: user}
+
+		saveCached(key, repos)
+		return GithubMsg{id: id, repos: repos, rate: rate}
+	}
+}
+
+// rateLimitMsg reports a failed fetch along with the quota or the wait that
+// GitHub sent with a rate limit error
+func rateLimitMsg(id string, err error) GithubMsg {
+	msg := GithubMsg{id: id, err: err}
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateErr) {
+		msg.rate = rateErr.Rate
+	} else if errors.As(err, &abuseErr) {
+		msg.retryAfter = abuseErr.GetRetryAfter()
 	}
+	return msg
 }
@@ -1,89 +1,232 @@
 package widgets
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/httpclient"
+	"github.com/xanzy/go-gitlab"
 )
 
-type gitlabUser struct {
-	Username string `json:"username"`
-	Name     string `json:"name"`
-	WebURL   string `json:"web_url"`
+// GitlabWidget displays GitLab project information
+type GitlabWidget struct {
+	BaseWidget
+	client         *gitlab.Client
+	clientErr      error
+	cacheKey       string
+	projects       []string
+	projectInfo    []ProjectInfo
+	err            error
+	updateInterval time.Duration
+	schedule       Schedule
+	scroll         ScrollView
 }
 
-type gitlabWidget struct {
-	user    gitlabUser
-	message string
-	err     error
+// ProjectInfo contains project information
+type ProjectInfo struct {
+	Name       string
+	Stars      int
+	Forks      int
+	OpenIssues int
+	OpenMRs    int
+	WebURL     string
 }
 
-// NewGitLabWidget constructs the GitLab widget.
-func NewGitLabWidget() Widget { return &gitlabWidget{} }
+// GitlabMsg contains GitLab information
+type GitlabMsg struct {
+	id       string
+	projects []ProjectInfo
+	err      error
+}
 
-func (g *gitlabWidget) Title() string { return "GitLab" }
+// WidgetID implements Addressed
+func (m GitlabMsg) WidgetID() string { return m.id }
 
-func (g *gitlabWidget) Init() tea.Cmd { return g.fetch() }
+func init() {
+	Register(Type{
+		Name:        "gitlab",
+		Description: "Stars, forks, open issues and merge requests of GitLab projects",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewGitlabWidget(
+				spec.ID,
+				spec.Options.String("token", ""),
+				spec.Options.Strings("projects"),
+				spec.Refresh,
+			), nil
+		},
+		Options: []Option{
+			{Name: "token", Kind: StringOption, Help: "API token or env:, file: or cmd: reference (default gitlab_token)", Secret: true},
+			{Name: "projects", Kind: StringListOption, Help: "namespace/project paths or project IDs (default gitlab_projects)", Check: checkProjectName},
+		},
+	})
+}
 
-func (g *gitlabWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch msg := msg.(type) {
-	case TickMsg:
-		return g, g.fetch()
-	case gitlabMsg:
-		g.user = msg.user
-		g.message = msg.message
-		g.err = msg.err
-		return g, Tick(10 * time.Minute)
+// checkProjectName accepts a numeric project ID or a namespace/project path
+func checkProjectName(name string) error {
+	if _, err := strconv.Atoi(name); err == nil {
+		return nil
+	}
+	namespace, project, ok := strings.Cut(name, "/")
+	if !ok || namespace == "" || project == "" || strings.HasSuffix(project, "/") || strings.Contains(name, " ") {
+		return fmt.Errorf("%q is not a project ID or namespace/project path", name)
 	}
-	return g, nil
+	return nil
 }
 
-func (g *gitlabWidget) View(width, _ int) string {
-	if g.err != nil {
-		return fmt.Sprintf("Error: %v", g.err)
+// NewGitlabWidget creates a new GitLab widget
+func NewGitlabWidget(id, token string, projects []string, refreshInterval int) *GitlabWidget {
+	// The client is kept across refreshes so connections are reused. The
+	// shared client retries, so go-gitlab's own retries are turned off.
+	client, err := gitlab.NewClient(token, gitlab.WithHTTPClient(httpclient.Client()), gitlab.WithoutRetries())
+	w := &GitlabWidget{
+		BaseWidget:     NewBaseWidget(id, "🦊 GitLab"),
+		client:         client,
+		clientErr:      err,
+		cacheKey:       cacheKey("gitlab", id, projects...),
+		projects:       projects,
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+		scroll:         NewScrollView(),
 	}
-	if g.user.Username == "" && g.message == "" {
-		return "Loading profile..."
+	// Show the last known data until the first fetch finishes
+	if saved, ok := loadCached(w.cacheKey, &w.projectInfo); ok {
+		w.scroll.SetContent(w.formatProjects())
+		w.MarkCached(saved)
 	}
-	if g.user.Username == "" {
-		return g.message
+	return w
+}
+
+// Init initializes the widget
+func (w *GitlabWidget) Init() tea.Cmd {
+	return w.fetchGitlabInfo()
+}
+
+// Update handles messages
+func (w *GitlabWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case GitlabMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.err != nil {
+			// Keep showing the last data; the footer shows the error
+			if !w.HasData() {
+				w.err = msg.err
+			}
+		} else {
+			w.projectInfo = msg.projects
+			w.scroll.SetContent(w.formatProjects())
+			w.err = nil
+			w.MarkUpdated()
+		}
+		return w, w.schedule.After(w.updateInterval)
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.fetchGitlabInfo()
+	case tea.KeyMsg, tea.MouseMsg:
+		return w, w.scroll.Update(msg)
 	}
-	return fmt.Sprintf("User: %s\nName: %s\nURL: %s", g.user.Username, g.user.Name, g.user.WebURL)
+	return w, nil
 }
 
-type gitlabMsg struct {
-	user    gitlabUser
-	message string
-	err     error
+// KeyBindings implements KeyHelper
+func (w *GitlabWidget) KeyBindings() []key.Binding {
+	return w.scroll.KeyBindings()
 }
 
-func (g *gitlabWidget) fetch() tea.Cmd {
-	return func() tea.Msg {
-		token := os.Getenv("GITLAB_TOKEN")
-		client := &http.Client{Timeout: 5 * time.Second}
-		req, _ := http.NewRequest("GET", "https://gitlab.com/api/v4/user", nil)
-		if token != "" {
-			req.Header.Set("PRIVATE-TOKEN", token)
+// Commands implements Commander, offering to open each project once its
+// address is known
+func (w *GitlabWidget) Commands() []Command {
+	var cmds []Command
+	for _, proj := range w.projectInfo {
+		if proj.WebURL == "" {
+			continue
 		}
-		resp, err := client.Do(req)
-		if err != nil {
-			return gitlabMsg{err: err}
+		url := proj.WebURL
+		cmds = append(cmds, Command{
+			Title: fmt.Sprintf("Open %s on GitLab", proj.Name),
+			Run:   func() tea.Cmd { return OpenURL(url) },
+		})
+	}
+	return cmds
+}
+
+// View renders the widget
+func (w *GitlabWidget) View() string {
+	var content string
+	if w.err != nil {
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if len(w.projectInfo) == 0 {
+		if len(w.projects) == 0 {
+			content = "No projects configured"
+		} else {
+			content = "Loading..."
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode == http.StatusUnauthorized {
-			return gitlabMsg{message: "Set GITLAB_TOKEN for private data"}
+	} else {
+		content = w.RenderScroll(&w.scroll)
+	}
+	return w.RenderContent(content)
+}
+
+// formatProjects renders one entry per project
+func (w *GitlabWidget) formatProjects() string {
+	var lines []string
+	for _, proj := range w.projectInfo {
+		lines = append(lines, fmt.Sprintf(
+			"%s\n⭐ %d  🍴 %d  📝 %d  🔀 %d",
+			proj.Name,
+			proj.Stars,
+			proj.Forks,
+			proj.OpenIssues,
+			proj.OpenMRs,
+		))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func (w *GitlabWidget) fetchGitlabInfo() tea.Cmd {
+	id, client, key := w.id, w.client, w.cacheKey
+	return func() tea.Msg {
+		if len(w.projects) == 0 {
+			return GitlabMsg{id: id, projects: []ProjectInfo{}}
 		}
-		if resp.StatusCode != http.StatusOK {
-			return gitlabMsg{message: fmt.Sprintf("API status: %s", resp.Status)}
+		if w.clientErr != nil {
+			return GitlabMsg{id: id, err: w.clientErr}
 		}
-		var user gitlabUser
-		if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-			return gitlabMsg{err: err}
+
+		ctx, cancel := httpclient.Context()
+		defer cancel()
+
+		var projects []ProjectInfo
+		for _, projectName := range w.projects {
+			project, _, err := client.Projects.GetProject(projectName, nil, gitlab.WithContext(ctx))
+			if err != nil {
+				return GitlabMsg{id: id, err: err}
+			}
+
+			// Get merge requests count
+			openState := "opened"
+			mrs, _, _ := client.MergeRequests.ListProjectMergeRequests(project.ID, &gitlab.ListProjectMergeRequestsOptions{
+				State: &openState,
+			}, gitlab.WithContext(ctx))
+
+			info := ProjectInfo{
+				Name:       projectName,
+				Stars:      project.StarCount,
+				Forks:      project.ForksCount,
+				OpenIssues: project.OpenIssuesCount,
+				OpenMRs:    len(mrs),
+				WebURL:     project.WebURL,
+			}
+			projects = append(projects, info)
 		}
-		return gitlabMsg{user: user}
+
+		saveCached(key, projects)
+		return GitlabMsg{id: id, projects: projects}
 	}
 }
@@ -1,67 +1,142 @@
 package widgets
 
 import (
+	"encoding/json"
 	"fmt"
-	"net"
-	"sort"
-	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/httpclient"
 )
 
-type ipWidget struct {
-	addresses []string
+// IPWidget displays IP information
+type IPWidget struct {
+	BaseWidget
+	ipInfo         IPInfo
+	cacheKey       string
+	err            error
+	updateInterval time.Duration
+	schedule       Schedule
 }
 
-// NewIPWidget constructs the IP address widget.
-func NewIPWidget() Widget { return &ipWidget{} }
+// IPInfo contains IP address information
+type IPInfo struct {
+	IP       string `json:"ip"`
+	City     string `json:"city"`
+	Region   string `json:"region"`
+	Country  string `json:"country"`
+	Loc      string `json:"loc"`
+	Org      string `json:"org"`
+	Timezone string `json:"timezone"`
+}
 
-func (i *ipWidget) Title() string { return "IP Info" }
+// IPMsg contains IP information
+type IPMsg struct {
+	id   string
+	info IPInfo
+	err  error
+}
 
-func (i *ipWidget) Init() tea.Cmd { return i.refresh() }
+// WidgetID implements Addressed
+func (m IPMsg) WidgetID() string { return m.id }
 
-func (i *ipWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch msg.(type) {
-	case TickMsg:
-		return i, i.refresh()
-	case ipMsg:
-		data := msg.(ipMsg)
-		i.addresses = data.addresses
-		return i, Tick(1 * time.Minute)
+func init() {
+	Register(Type{
+		Name:        "ip",
+		Description: "Public IP address, location and ISP from ipinfo.io",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewIPWidget(spec.ID, spec.Refresh), nil
+		},
+	})
+}
+
+// NewIPWidget creates a new IP information widget
+func NewIPWidget(id string, refreshInterval int) *IPWidget {
+	w := &IPWidget{
+		BaseWidget:     NewBaseWidget(id, "🌐 IP Information"),
+		cacheKey:       cacheKey("ip", id),
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+	}
+	// Show the last known address until the first fetch finishes
+	if saved, ok := loadCached(w.cacheKey, &w.ipInfo); ok {
+		w.MarkCached(saved)
 	}
-	return i, nil
+	return w
 }
 
-func (i *ipWidget) View(width, _ int) string {
-	if len(i.addresses) == 0 {
-		return "Discovering network interfaces..."
+// Init initializes the widget
+func (w *IPWidget) Init() tea.Cmd {
+	return w.fetchIPInfo()
+}
+
+// Update handles messages
+func (w *IPWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case IPMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.err != nil {
+			// Keep showing the last data; the footer shows the error
+			if !w.HasData() {
+				w.err = msg.err
+			}
+		} else {
+			w.ipInfo = msg.info
+			w.err = nil
+			w.MarkUpdated()
+		}
+		return w, w.schedule.After(w.updateInterval)
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.fetchIPInfo()
 	}
-	return strings.Join(i.addresses, "\n")
+	return w, nil
 }
 
-type ipMsg struct{ addresses []string }
+// View renders the widget
+func (w *IPWidget) View() string {
+	var content string
+	if w.err != nil {
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if w.ipInfo.IP == "" {
+		content = "Loading IP info..."
+	} else {
+		content = fmt.Sprintf(
+			"IP:       %s\n"+
+				"Location: %s, %s\n"+
+				"Country:  %s\n"+
+				"Timezone: %s\n"+
+				"ISP:      %s",
+			w.ipInfo.IP,
+			w.ipInfo.City,
+			w.ipInfo.Region,
+			w.ipInfo.Country,
+			w.ipInfo.Timezone,
+			w.ipInfo.Org,
+		)
+	}
+	return w.RenderContent(content)
+}
 
-func (i *ipWidget) refresh() tea.Cmd {
+func (w *IPWidget) fetchIPInfo() tea.Cmd {
+	id, key := w.id, w.cacheKey
 	return func() tea.Msg {
-		var addrs []string
-		interfaces, err := net.Interfaces()
+		ctx, cancel := httpclient.Context()
+		defer cancel()
+		body, err := httpclient.Fetch(ctx, "https://ipinfo.io/json")
 		if err != nil {
-			return ipMsg{addresses: []string{fmt.Sprintf("Error: %v", err)}}
-		}
-		for _, iface := range interfaces {
-			if iface.Flags&net.FlagUp == 0 {
-				continue
-			}
-			iaddrs, _ := iface.Addrs()
-			for _, addr := range iaddrs {
-				addrs = append(addrs, fmt.Sprintf("%s: %s", iface.Name, addr.String()))
-			}
+			return IPMsg{id: id, err: err}
 		}
-		sort.Strings(addrs)
-		if len(addrs) == 0 {
-			addrs = []string{"No active addresses"}
+
+		var info IPInfo
+		if err := json.Unmarshal(body, &info); err != nil {
+			return IPMsg{id: id, err: err}
 		}
-		return ipMsg{addresses: addrs}
+
+		saveCached(key, info)
+		return IPMsg{id: id, info: info}
 	}
 }
@@ -0,0 +1,155 @@
+package widgets
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/internal/logging"
+	"github.com/cj3636/gotui/internal/theme"
+)
+
+// LogWidget lists recent log records from every widget, newest first
+type LogWidget struct {
+	BaseWidget
+	level slog.Level
+	// count is the number of records logged when the list was last built
+	count          uint64
+	empty          bool
+	updateInterval time.Duration
+	schedule       Schedule
+	scroll         ScrollView
+}
+
+func init() {
+	Register(Type{
+		Name:        "log",
+		Description: "Recent errors and warnings from all widgets, with times and widget IDs",
+		Factory: func(spec Spec) (Widget, error) {
+			level, err := parseShownLevel(spec.Options.String("level", "warn"))
+			if err != nil {
+				return nil, err
+			}
+			return NewLogWidget(spec.ID, level, spec.Refresh), nil
+		},
+		Options: []Option{
+			{Name: "level", Kind: StringOption, Help: "least severe level shown: info, warn or error (default warn)", Check: checkLogLevel},
+		},
+	})
+}
+
+func checkLogLevel(name string) error {
+	_, err := parseShownLevel(name)
+	return err
+}
+
+// parseShownLevel parses the level option. Debug records aren't kept for
+// the widget, so the least severe level it can show is info.
+func parseShownLevel(name string) (slog.Level, error) {
+	level, err := logging.ParseLevel(name)
+	if err != nil {
+		return 0, err
+	}
+	if level < slog.LevelInfo {
+		return 0, fmt.Errorf("%q is below info; the log widget shows info, warn and error", name)
+	}
+	return level, nil
+}
+
+// NewLogWidget creates a widget showing log records at level and above,
+// checking for new ones every refreshInterval seconds (every second if 0)
+func NewLogWidget(id string, level slog.Level, refreshInterval int) *LogWidget {
+	interval := time.Duration(refreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &LogWidget{
+		BaseWidget:     NewBaseWidget(id, "📜 Log"),
+		level:          level,
+		empty:          true,
+		updateInterval: interval,
+		schedule:       NewSchedule(id),
+		scroll:         NewScrollView(),
+	}
+}
+
+// Init initializes the widget
+func (w *LogWidget) Init() tea.Cmd {
+	// The log is there to read right away, even if it is empty
+	w.MarkLoaded()
+	return w.schedule.After(w.updateInterval)
+}
+
+// Update handles messages
+func (w *LogWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case RefreshMsg:
+		// The tick only redraws the dashboard; View picks up new records
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.schedule.After(w.updateInterval)
+	case tea.KeyMsg, tea.MouseMsg:
+		return w, w.scroll.Update(msg)
+	}
+	return w, nil
+}
+
+// load lists the records logged since the last look, if any. Records are
+// logged from every goroutine, so it runs when drawing rather than on a
+// message, and a frame never misses the ones logged before it.
+func (w *LogWidget) load() {
+	entries, count := logging.Recent(w.level)
+	if count == w.count {
+		return
+	}
+	w.count = count
+	w.empty = len(entries) == 0
+
+	t := w.Theme()
+	muted := lipgloss.NewStyle().Foreground(t.Muted)
+	levels := map[slog.Level]lipgloss.Style{
+		slog.LevelError: lipgloss.NewStyle().Bold(true).Foreground(t.Error),
+		slog.LevelWarn:  lipgloss.NewStyle().Bold(true).Foreground(t.Focus),
+		slog.LevelInfo:  lipgloss.NewStyle().Foreground(t.Success),
+	}
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		source := ""
+		if e.Widget != "" {
+			source = e.Widget + ": "
+		}
+		lines[i] = fmt.Sprintf("%s %s %s%s",
+			muted.Render(e.Time.Format("15:04:05")),
+			levels[e.Level].Render(e.Level.String()),
+			source,
+			e.Message,
+		)
+	}
+	w.scroll.SetContent(strings.Join(lines, "\n"))
+}
+
+// SetTheme also recolors the records already listed
+func (w *LogWidget) SetTheme(t theme.Theme) {
+	w.BaseWidget.SetTheme(t)
+	w.count = 0
+}
+
+// KeyBindings implements KeyHelper
+func (w *LogWidget) KeyBindings() []key.Binding {
+	return w.scroll.KeyBindings()
+}
+
+// View renders the widget
+func (w *LogWidget) View() string {
+	w.load()
+	if w.empty {
+		return w.RenderContent(fmt.Sprintf("Nothing at %s level or above logged yet", strings.ToLower(w.level.String())))
+	}
+	return w.RenderContent(w.RenderScroll(&w.scroll))
+}
@@ -0,0 +1,17 @@
+package widgets
+
+import "testing"
+
+func TestCheckLogLevel(t *testing.T) {
+	for _, name := range []string{"info", "warn", "error", "WARN"} {
+		if err := checkLogLevel(name); err != nil {
+			t.Errorf("checkLogLevel(%q) = %v, want nil", name, err)
+		}
+	}
+	// Debug records aren't kept, so the widget can't show them
+	for _, name := range []string{"debug", "loud", ""} {
+		if err := checkLogLevel(name); err == nil {
+			t.Errorf("checkLogLevel(%q) = nil, want an error", name)
+		}
+	}
+}
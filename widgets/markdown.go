@@ -3,74 +3,139 @@ package widgets
 import (
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 )
 
-const defaultMarkdown = "# Welcome to GoTUI\n\n" +
-	"Use `MARKDOWN_PATH` to point at a local file. This widget renders Markdown using Glamour (the renderer behind Glow) so you can keep notes, dashboards, and runbooks nearby.\n"
+// MarkdownWidget displays rendered markdown content
+type MarkdownWidget struct {
+	BaseWidget
+	filename       string
+	content        string
+	err            error
+	scroll         ScrollView
+	updateInterval time.Duration
+	schedule       Schedule
+}
 
-// markdownWidget shows rendered markdown content using Glamour (Glow's renderer).
-type markdownWidget struct {
+// MarkdownMsg contains rendered markdown content
+type MarkdownMsg struct {
+	id      string
 	content string
-	width   int
+	err     error
 }
 
-// NewMarkdownWidget constructs the markdown widget.
-func NewMarkdownWidget() Widget {
-	return &markdownWidget{}
+// WidgetID implements Addressed
+func (m MarkdownMsg) WidgetID() string { return m.id }
+
+func init() {
+	Register(Type{
+		Name:        "markdown",
+		Description: "A Markdown file rendered with Glamour",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewMarkdownWidget(spec.ID, spec.Options.String("file", ""), spec.Refresh), nil
+		},
+		Options: []Option{
+			{Name: "file", Kind: StringOption, Help: "path to the file (default markdown_file)"},
+		},
+	})
 }
 
-func (m *markdownWidget) Title() string { return "Markdown" }
+// NewMarkdownWidget creates a new markdown viewer widget. The file is read
+// again every refreshInterval seconds, or only on request if 0.
+func NewMarkdownWidget(id, filename string, refreshInterval int) *MarkdownWidget {
+	return &MarkdownWidget{
+		BaseWidget:     NewBaseWidget(id, "📝 Markdown"),
+		filename:       filename,
+		scroll:         NewScrollView(),
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+	}
+}
 
-func (m *markdownWidget) Init() tea.Cmd { return m.load() }
+// Init initializes the widget
+func (w *MarkdownWidget) Init() tea.Cmd {
+	if w.filename != "" {
+		return w.loadMarkdown()
+	}
+	// Nothing to load
+	w.MarkLoaded()
+	return nil
+}
 
-func (m *markdownWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+// Update handles messages
+func (w *MarkdownWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-	case markdownMsg:
-		m.content = msg.content
+	case MarkdownMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.err != nil {
+			w.err = msg.err
+		} else {
+			w.content = msg.content
+			w.scroll.SetContent(msg.content)
+			w.MarkUpdated()
+			w.err = nil
+		}
+		if w.updateInterval > 0 {
+			return w, w.schedule.After(w.updateInterval)
+		}
+	case RefreshMsg:
+		// Without a refresh interval the file is only reloaded on request
+		if w.schedule.Due(msg) && w.filename != "" {
+			return w, w.loadMarkdown()
+		}
+	case tea.KeyMsg, tea.MouseMsg:
+		return w, w.scroll.Update(msg)
 	}
-	return m, nil
+	return w, nil
 }
 
-func (m *markdownWidget) View(width, _ int) string {
-	if m.content == "" {
-		return "Waiting for markdown data..."
-	}
-	rendererWidth := width
-	if rendererWidth < 20 {
-		rendererWidth = 20
-	}
-	r, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(rendererWidth),
-	)
-	if err != nil {
-		return m.content
-	}
-	rendered, err := r.Render(strings.TrimSpace(m.content))
-	if err != nil {
-		return m.content
-	}
-	return rendered
+// KeyBindings implements KeyHelper
+func (w *MarkdownWidget) KeyBindings() []key.Binding {
+	return w.scroll.KeyBindings()
 }
 
-type markdownMsg struct{ content string }
+// View renders the widget
+func (w *MarkdownWidget) View() string {
+	var content string
+	if w.filename == "" {
+		content = "No markdown file configured"
+	} else if w.err != nil {
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if w.content == "" {
+		content = "Loading..."
+	} else {
+		content = w.RenderScroll(&w.scroll)
+	}
+	return w.RenderContent(content)
+}
 
-func (m *markdownWidget) load() tea.Cmd {
+func (w *MarkdownWidget) loadMarkdown() tea.Cmd {
+	id := w.id
 	return func() tea.Msg {
-		path := os.Getenv("MARKDOWN_PATH")
-		if path == "" {
-			return markdownMsg{content: defaultMarkdown}
+		data, err := os.ReadFile(w.filename)
+		if err != nil {
+			return MarkdownMsg{id: id, err: err}
 		}
-		data, err := os.ReadFile(path)
+
+		// Use glamour to render markdown
+		r, err := glamour.NewTermRenderer(
+			glamour.WithAutoStyle(),
+			glamour.WithWordWrap(60),
+		)
 		if err != nil {
-			return markdownMsg{content: fmt.Sprintf("Failed to read %s: %v", path, err)}
+			return MarkdownMsg{id: id, err: err}
 		}
-		return markdownMsg{content: string(data)}
+
+		rendered, err := r.Render(string(data))
+		if err != nil {
+			return MarkdownMsg{id: id, err: err}
+		}
+
+		return MarkdownMsg{id: id, content: rendered}
 	}
 }
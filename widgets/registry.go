@@ -0,0 +1,193 @@
+package widgets
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Spec describes one configured widget instance
+type Spec struct {
+	// ID identifies the instance; addressed messages carry it
+	ID string
+	// Type selects the registered factory
+	Type string
+	// Title replaces the widget's default title when set
+	Title string
+	// Refresh is the refresh interval in seconds
+	Refresh int
+	// Options holds the type-specific settings from the config entry
+	Options Options
+}
+
+// Options holds the type-specific settings of a widget entry
+type Options map[string]any
+
+// String returns the option as a string, or def when it is unset
+func (o Options) String(key, def string) string {
+	v, ok := o[key]
+	if !ok || v == nil {
+		return def
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// Int returns the option as an int, or def when it is unset or not a number
+func (o Options) Int(key string, def int) int {
+	switch v := o[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// Strings returns the option as a list of strings
+func (o Options) Strings(key string) []string {
+	switch v := o[key].(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+// Factory builds a widget from its spec
+type Factory func(spec Spec) (Widget, error)
+
+// OptionKind is the shape an option's value takes in the config
+type OptionKind int
+
+const (
+	// StringOption is a single scalar value
+	StringOption OptionKind = iota
+	// IntOption is a whole number
+	IntOption
+	// StringListOption is a list of scalars; a single scalar counts as a
+	// list of one
+	StringListOption
+)
+
+// Option declares a type-specific option a widget type accepts. Config
+// validation rejects options a type doesn't declare.
+type Option struct {
+	Name string
+	Kind OptionKind
+	// Help is a short description shown by "gotui widgets list"
+	Help string
+	// Check validates the value, or each item of a list; nil accepts any
+	// value of the right kind
+	Check func(value string) error
+	// Secret marks values that may be env:, file: or cmd: references,
+	// resolved when the config loads and hidden from logs and errors
+	Secret bool
+}
+
+// Type describes a widget type: how to build it and what it accepts
+type Type struct {
+	Name string
+	// Description is a one-line summary shown by "gotui widgets list"
+	Description string
+	Factory     Factory
+	Options     []Option
+	// IgnoresRefresh marks types that keep their own time, or load once,
+	// so that a refresh setting on their entries is reported as unused
+	IgnoresRefresh bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Type{}
+)
+
+// Register makes a widget type available to the config under t.Name. It is
+// meant to be called from init functions, including in third-party
+// packages, and panics if the name is already registered.
+func Register(t Type) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if t.Factory == nil {
+		panic("widgets: Register factory is nil for " + t.Name)
+	}
+	if _, dup := registry[t.Name]; dup {
+		panic("widgets: Register called twice for " + t.Name)
+	}
+	registry[t.Name] = t
+}
+
+// Lookup returns the registered widget type called name
+func Lookup(name string) (Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	t, ok := registry[name]
+	return t, ok
+}
+
+// OptionsFor returns the options declared by a widget type, and whether the
+// type is registered
+func OptionsFor(typ string) ([]Option, bool) {
+	t, ok := Lookup(typ)
+	return t.Options, ok
+}
+
+// LookupOption returns the option called name declared by a widget type
+func LookupOption(typ, name string) (Option, bool) {
+	options, _ := OptionsFor(typ)
+	for _, opt := range options {
+		if opt.Name == name {
+			return opt, true
+		}
+	}
+	return Option{}, false
+}
+
+// Types returns the registered widget types in sorted order
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New builds a widget from spec using the factory registered for its type
+func New(spec Spec) (Widget, error) {
+	t, ok := Lookup(spec.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown widget type %q", spec.Type)
+	}
+
+	widget, err := t.Factory(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s widget %q: %w", spec.Type, spec.ID, err)
+	}
+
+	if spec.Title != "" {
+		if t, ok := widget.(interface{ SetTitle(string) }); ok {
+			t.SetTitle(spec.Title)
+		}
+	}
+	return widget, nil
+}
@@ -0,0 +1,82 @@
+package widgets
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Addressed is implemented by messages that belong to a single widget. The
+// dashboard delivers them only to the widget whose ID matches instead of
+// broadcasting them to every panel.
+type Addressed interface {
+	WidgetID() string
+}
+
+// RefreshMsg tells the widget identified by ID that its refresh interval has
+// elapsed.
+type RefreshMsg struct {
+	ID     string
+	Time   time.Time
+	seq    uint64
+	forced bool
+}
+
+// WidgetID implements Addressed.
+func (m RefreshMsg) WidgetID() string { return m.ID }
+
+// Forced reports whether the refresh was asked for with RefreshNow rather
+// than scheduled.
+func (m RefreshMsg) Forced() bool { return m.forced }
+
+// RefreshNow returns a command that refreshes the widget identified by id
+// right away. Its schedule counts the refresh as due whichever tick it is
+// waiting on, and re-arms from there.
+func RefreshNow(id string) tea.Cmd {
+	return func() tea.Msg {
+		return RefreshMsg{ID: id, Time: time.Now(), forced: true}
+	}
+}
+
+// lastSeq numbers ticks across all schedules, so a widget rebuilt under the
+// same ID never mistakes its predecessor's pending tick for its own.
+var lastSeq atomic.Uint64
+
+// Schedule tracks the pending refresh for one widget. Every call to After
+// supersedes the tick that is still in flight, so a widget never has more
+// than one live timer no matter how often it is re-armed.
+type Schedule struct {
+	id  string
+	seq uint64
+}
+
+// NewSchedule returns a schedule whose ticks are addressed to id.
+func NewSchedule(id string) Schedule {
+	return Schedule{id: id}
+}
+
+// After arms the schedule to deliver a RefreshMsg once d has elapsed.
+func (s *Schedule) After(d time.Duration) tea.Cmd {
+	s.seq = lastSeq.Add(1)
+	id, seq := s.id, s.seq
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return RefreshMsg{ID: id, Time: t, seq: seq}
+	})
+}
+
+// Due reports whether msg is the tick this schedule is currently waiting on,
+// or a forced refresh. Ticks addressed to other widgets and ticks superseded
+// by a later call to After are ignored. A forced refresh also cancels the
+// pending tick, so the widget's next After starts the interval afresh
+// instead of a second refresh following close behind.
+func (s *Schedule) Due(msg RefreshMsg) bool {
+	if msg.ID != s.id {
+		return false
+	}
+	if msg.forced {
+		s.seq = lastSeq.Add(1)
+		return true
+	}
+	return msg.seq == s.seq
+}
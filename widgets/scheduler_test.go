@@ -0,0 +1,84 @@
+package widgets
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tick runs a schedule's command and returns the RefreshMsg it delivers
+func tick(t *testing.T, cmd tea.Cmd) RefreshMsg {
+	t.Helper()
+	msg, ok := cmd().(RefreshMsg)
+	if !ok {
+		t.Fatalf("schedule delivered %T, want RefreshMsg", msg)
+	}
+	return msg
+}
+
+func TestScheduleDue(t *testing.T) {
+	s := NewSchedule("clock")
+	msg := tick(t, s.After(time.Millisecond))
+	if msg.ID != "clock" {
+		t.Errorf("tick addressed to %q, want clock", msg.ID)
+	}
+	if !s.Due(msg) {
+		t.Error("the pending tick isn't due")
+	}
+
+	other := NewSchedule("calendar")
+	if other.Due(msg) {
+		t.Error("a tick for another widget is due")
+	}
+}
+
+func TestScheduleSupersedes(t *testing.T) {
+	s := NewSchedule("clock")
+	first := s.After(time.Millisecond)
+	second := s.After(time.Millisecond)
+
+	if s.Due(tick(t, first)) {
+		t.Error("a superseded tick is due")
+	}
+	if !s.Due(tick(t, second)) {
+		t.Error("the latest tick isn't due")
+	}
+}
+
+// A widget rebuilt under the same ID ignores its predecessor's tick
+func TestScheduleRebuilt(t *testing.T) {
+	old := NewSchedule("weather")
+	pending := old.After(time.Millisecond)
+
+	rebuilt := NewSchedule("weather")
+	rebuilt.After(time.Hour)
+	if rebuilt.Due(tick(t, pending)) {
+		t.Error("the old schedule's tick is due on the rebuilt one")
+	}
+}
+
+func TestScheduleForced(t *testing.T) {
+	s := NewSchedule("github")
+	pending := s.After(time.Millisecond)
+
+	forced, ok := RefreshNow("github")().(RefreshMsg)
+	if !ok || !forced.Forced() {
+		t.Fatalf("RefreshNow delivered %#v, want a forced RefreshMsg", forced)
+	}
+	if !s.Due(forced) {
+		t.Error("a forced refresh isn't due")
+	}
+	// The refresh starts the interval over, so the old tick mustn't
+	// trigger a second one right behind it
+	if s.Due(tick(t, pending)) {
+		t.Error("the tick pending before a forced refresh is still due")
+	}
+	if !s.Due(tick(t, s.After(time.Millisecond))) {
+		t.Error("the tick armed after a forced refresh isn't due")
+	}
+
+	if other := NewSchedule("gitlab"); other.Due(forced) {
+		t.Error("a forced refresh for another widget is due")
+	}
+}
@@ -0,0 +1,115 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/internal/theme"
+)
+
+// scrollKeys are the viewport bindings used by ScrollView. Arrow keys and
+// hjkl move focus between panels, so line scrolling uses their shifted forms.
+var scrollKeys = viewport.KeyMap{
+	PageDown:     key.NewBinding(key.WithKeys("pgdown", " ", "f"), key.WithHelp("f/pgdn", "page down")),
+	PageUp:       key.NewBinding(key.WithKeys("pgup", "b"), key.WithHelp("b/pgup", "page up")),
+	HalfPageDown: key.NewBinding(key.WithKeys("d", "ctrl+d"), key.WithHelp("d", "½ page down")),
+	HalfPageUp:   key.NewBinding(key.WithKeys("u", "ctrl+u"), key.WithHelp("u", "½ page up")),
+	Down:         key.NewBinding(key.WithKeys("J", "shift+down"), key.WithHelp("J", "down")),
+	Up:           key.NewBinding(key.WithKeys("K", "shift+up"), key.WithHelp("K", "up")),
+}
+
+var (
+	scrollTopKey    = key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("g", "top"))
+	scrollBottomKey = key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("G", "bottom"))
+)
+
+// ScrollView is a scrollable viewport for widget content that can be longer
+// than its panel. Pass key and mouse messages to Update and render it with
+// BaseWidget.RenderScroll.
+type ScrollView struct {
+	viewport viewport.Model
+	content  string
+	wrapped  int
+}
+
+// NewScrollView creates an empty scroll view
+func NewScrollView() ScrollView {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = scrollKeys
+	return ScrollView{viewport: vp}
+}
+
+// SetContent replaces the content, keeping the scroll position where possible
+func (s *ScrollView) SetContent(content string) {
+	s.content = strings.TrimRight(content, "\n")
+	s.wrapped = 0
+}
+
+// KeyBindings returns the keys that scroll the view
+func (s *ScrollView) KeyBindings() []key.Binding {
+	return []key.Binding{
+		scrollKeys.Down, scrollKeys.Up,
+		scrollKeys.PageDown, scrollKeys.PageUp,
+		scrollKeys.HalfPageDown, scrollKeys.HalfPageUp,
+		scrollTopKey, scrollBottomKey,
+	}
+}
+
+// Update scrolls in response to key and mouse wheel messages
+func (s *ScrollView) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, scrollTopKey):
+			s.viewport.GotoTop()
+			return nil
+		case key.Matches(msg, scrollBottomKey):
+			s.viewport.GotoBottom()
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+	return cmd
+}
+
+// Render lays the content out in width x height cells. When the content is
+// taller than that, the last line shows which lines are visible.
+// BaseWidget.RenderScroll does the same in the widget's theme.
+func (s *ScrollView) Render(width, height int) string {
+	return s.render(width, height, theme.Default().Muted)
+}
+
+func (s *ScrollView) render(width, height int, indicatorColor lipgloss.Color) string {
+	if width < 1 || height < 1 {
+		return ""
+	}
+
+	// Wrap once per width so line counts match what is drawn
+	if s.wrapped != width {
+		s.viewport.SetContent(lipgloss.NewStyle().Width(width).Render(s.content))
+		s.wrapped = width
+	}
+
+	s.viewport.Width = width
+	s.viewport.Height = height
+	total := s.viewport.TotalLineCount()
+	if total <= height || height < 2 {
+		s.viewport.SetYOffset(0)
+		return s.viewport.View()
+	}
+
+	s.viewport.Height = height - 1
+	s.viewport.SetYOffset(s.viewport.YOffset)
+	top := s.viewport.YOffset + 1
+	bottom := min(s.viewport.YOffset+s.viewport.Height, total)
+	indicator := lipgloss.NewStyle().
+		Foreground(indicatorColor).
+		Render(fmt.Sprintf("↕ %d–%d of %d (%.0f%%)", top, bottom, total, s.viewport.ScrollPercent()*100))
+
+	return s.viewport.View() + "\n" + indicator
+}
@@ -18,14 +18,29 @@ type SMARTWidget struct {
 
 // SMARTMsg contains SMART data
 type SMARTMsg struct {
+	id   string
 	data string
 	err  error
 }
 
+// WidgetID implements Addressed
+func (m SMARTMsg) WidgetID() string { return m.id }
+
+func init() {
+	Register(Type{
+		Name:        "smart",
+		Description: "Disk usage and SMART health of detected drives",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewSMARTWidget(spec.ID), nil
+		},
+		IgnoresRefresh: true,
+	})
+}
+
 // NewSMARTWidget creates a new SMART status widget
-func NewSMARTWidget() *SMARTWidget {
+func NewSMARTWidget(id string) *SMARTWidget {
 	return &SMARTWidget{
-		BaseWidget: NewBaseWidget("💾 SMART Status"),
+		BaseWidget: NewBaseWidget(id, "💾 SMART Status"),
 	}
 }
 
@@ -38,12 +53,20 @@ func (w *SMARTWidget) Init() tea.Cmd {
 func (w *SMARTWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
 	switch msg := msg.(type) {
 	case SMARTMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
 		if msg.err != nil {
 			w.err = msg.err
 		} else {
 			w.smartData = msg.data
+			w.MarkUpdated()
 			w.err = nil
 		}
+	case RefreshMsg:
+		// Only refreshed on request
+		if msg.ID == w.id && msg.Forced() {
+			return w, w.fetchSMARTData()
+		}
 	}
 	return w, nil
 }
@@ -62,6 +85,7 @@ func (w *SMARTWidget) View() string {
 }
 
 func (w *SMARTWidget) fetchSMARTData() tea.Cmd {
+	id := w.id
 	return func() tea.Msg {
 		// Check if smartctl is available
 		var cmd *exec.Cmd
@@ -70,12 +94,12 @@ func (w *SMARTWidget) fetchSMARTData() tea.Cmd {
 			// Try to get basic disk info without root
 			cmd = exec.Command("df", "-h", "/")
 		} else {
-			return SMARTMsg{err: fmt.Errorf("SMART monitoring not supported on %s", runtime.GOOS)}
+			return SMARTMsg{id: id, err: fmt.Errorf("SMART monitoring not supported on %s", runtime.GOOS)}
 		}
 
 		output, err := cmd.CombinedOutput()
 		if err != nil {
-			return SMARTMsg{err: err}
+			return SMARTMsg{id: id, err: err}
 		}
 
 		// Parse disk information
@@ -118,6 +142,6 @@ func (w *SMARTWidget) fetchSMARTData() tea.Cmd {
 		}
 
 		data := strings.Join(result, "\n")
-		return SMARTMsg{data: data}
+		return SMARTMsg{id: id, data: data}
 	}
 }
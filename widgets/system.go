@@ -3,96 +3,164 @@ package widgets
 import (
 	"fmt"
 	"runtime"
-	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
-// systemWidget reports CPU, memory, and disk stats.
-type systemWidget struct {
-	cpuLoad   float64
-	memUsed   float64
-	memTotal  float64
-	diskUsed  float64
-	diskTotal float64
-	err       error
+// SystemWidget displays system resource information
+type SystemWidget struct {
+	BaseWidget
+	cpuPercent     float64
+	memPercent     float64
+	memUsed        uint64
+	memTotal       uint64
+	diskPercent    float64
+	diskUsed       uint64
+	diskTotal      uint64
+	updateInterval time.Duration
+	schedule       Schedule
 }
 
-// NewSystemWidget constructs the system monitor widget.
-func NewSystemWidget() Widget { return &systemWidget{} }
+// SystemMsg contains system information
+type SystemMsg struct {
+	id          string
+	cpuPercent  float64
+	memPercent  float64
+	memUsed     uint64
+	memTotal    uint64
+	diskPercent float64
+	diskUsed    uint64
+	diskTotal   uint64
+}
 
-func (s *systemWidget) Title() string { return "System" }
+// WidgetID implements Addressed
+func (m SystemMsg) WidgetID() string { return m.id }
 
-func (s *systemWidget) Init() tea.Cmd { return s.sample() }
+func init() {
+	Register(Type{
+		Name:        "system",
+		Description: "CPU, memory and disk usage",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewSystemWidget(spec.ID, spec.Refresh), nil
+		},
+	})
+}
 
-func (s *systemWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch msg.(type) {
-	case TickMsg:
-		return s, s.sample()
-	case systemMsg:
-		data := msg.(systemMsg)
-		s.cpuLoad = data.cpuLoad
-		s.memUsed = data.memUsed
-		s.memTotal = data.memTotal
-		s.diskUsed = data.diskUsed
-		s.diskTotal = data.diskTotal
-		s.err = data.err
-		return s, Tick(5 * time.Second)
+// NewSystemWidget creates a new system resource widget
+func NewSystemWidget(id string, refreshInterval int) *SystemWidget {
+	return &SystemWidget{
+		BaseWidget:     NewBaseWidget(id, "💻 System Resources"),
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
 	}
-	return s, nil
 }
 
-func (s *systemWidget) View(width, _ int) string {
-	if s.err != nil {
-		return fmt.Sprintf("Error: %v", s.err)
-	}
-	if s.memTotal == 0 {
-		return "Collecting metrics..."
+// Init initializes the widget
+func (w *SystemWidget) Init() tea.Cmd {
+	return w.fetchSystemInfo()
+}
+
+// Update handles messages
+func (w *SystemWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SystemMsg:
+		w.MarkLoaded()
+		w.cpuPercent = msg.cpuPercent
+		w.memPercent = msg.memPercent
+		w.memUsed = msg.memUsed
+		w.memTotal = msg.memTotal
+		w.diskPercent = msg.diskPercent
+		w.diskUsed = msg.diskUsed
+		w.diskTotal = msg.diskTotal
+		w.MarkUpdated()
+		return w, w.schedule.After(w.updateInterval)
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.fetchSystemInfo()
 	}
-	smart := "SMART: smartctl not detected"
-	return strings.Join([]string{
-		fmt.Sprintf("CPU Load: %0.1f%%", s.cpuLoad),
-		fmt.Sprintf("Memory: %0.1f / %0.1f GiB", s.memUsed, s.memTotal),
-		fmt.Sprintf("Disk: %0.1f / %0.1f GiB", s.diskUsed, s.diskTotal),
-		fmt.Sprintf("Go Version: %s", runtime.Version()),
-		smart,
-	}, "\n")
+	return w, nil
 }
 
-type systemMsg struct {
-	cpuLoad   float64
-	memUsed   float64
-	memTotal  float64
-	diskUsed  float64
-	diskTotal float64
-	err       error
+// View renders the widget
+func (w *SystemWidget) View() string {
+	content := fmt.Sprintf(
+		"CPU:  %5.1f%%\n"+
+			"RAM:  %5.1f%% (%s / %s)\n"+
+			"Disk: %5.1f%% (%s / %s)\n"+
+			"OS:   %s/%s",
+		w.cpuPercent,
+		w.memPercent,
+		formatBytes(w.memUsed),
+		formatBytes(w.memTotal),
+		w.diskPercent,
+		formatBytes(w.diskUsed),
+		formatBytes(w.diskTotal),
+		runtime.GOOS,
+		runtime.GOARCH,
+	)
+	return w.RenderContent(content)
 }
 
-func (s *systemWidget) sample() tea.Cmd {
+func (w *SystemWidget) fetchSystemInfo() tea.Cmd {
+	id := w.id
 	return func() tea.Msg {
-		cpuPercent, err := cpu.Percent(0, false)
-		if err != nil {
-			return systemMsg{err: err}
+		// Get CPU percentage
+		cpuPercentages, err := cpu.Percent(time.Second, false)
+		cpuPercent := 0.0
+		if err == nil && len(cpuPercentages) > 0 {
+			cpuPercent = cpuPercentages[0]
 		}
+
+		// Get memory stats
 		memStats, err := mem.VirtualMemory()
-		if err != nil {
-			return systemMsg{err: err}
+		memPercent := 0.0
+		memUsed := uint64(0)
+		memTotal := uint64(0)
+		if err == nil && memStats != nil {
+			memPercent = memStats.UsedPercent
+			memUsed = memStats.Used
+			memTotal = memStats.Total
 		}
+
+		// Get disk stats
 		diskStats, err := disk.Usage("/")
-		if err != nil {
-			return systemMsg{err: err}
+		diskPercent := 0.0
+		diskUsed := uint64(0)
+		diskTotal := uint64(0)
+		if err == nil && diskStats != nil {
+			diskPercent = diskStats.UsedPercent
+			diskUsed = diskStats.Used
+			diskTotal = diskStats.Total
 		}
-		return systemMsg{
-			cpuLoad:   cpuPercent[0],
-			memUsed:   float64(memStats.Used) / (1024 * 1024 * 1024),
-			memTotal:  float64(memStats.Total) / (1024 * 1024 * 1024),
-			diskUsed:  float64(diskStats.Used) / (1024 * 1024 * 1024),
-			diskTotal: float64(diskStats.Total) / (1024 * 1024 * 1024),
+
+		return SystemMsg{
+			id:          id,
+			cpuPercent:  cpuPercent,
+			memPercent:  memPercent,
+			memUsed:     memUsed,
+			memTotal:    memTotal,
+			diskPercent: diskPercent,
+			diskUsed:    diskUsed,
+			diskTotal:   diskTotal,
 		}
 	}
 }
+
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
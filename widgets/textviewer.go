@@ -0,0 +1,126 @@
+package widgets
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TextViewerWidget displays content from a text file
+type TextViewerWidget struct {
+	BaseWidget
+	filename       string
+	content        string
+	err            error
+	scroll         ScrollView
+	updateInterval time.Duration
+	schedule       Schedule
+}
+
+// TextMsg contains text file content
+type TextMsg struct {
+	id      string
+	content string
+	err     error
+}
+
+// WidgetID implements Addressed
+func (m TextMsg) WidgetID() string { return m.id }
+
+func init() {
+	Register(Type{
+		Name:        "text",
+		Description: "Contents of a plain text file",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewTextViewerWidget(spec.ID, spec.Options.String("file", ""), spec.Refresh), nil
+		},
+		Options: []Option{
+			{Name: "file", Kind: StringOption, Help: "path to the file (default text_file)"},
+		},
+	})
+}
+
+// NewTextViewerWidget creates a new text viewer widget. The file is read
+// again every refreshInterval seconds, or only on request if 0.
+func NewTextViewerWidget(id, filename string, refreshInterval int) *TextViewerWidget {
+	return &TextViewerWidget{
+		BaseWidget:     NewBaseWidget(id, "📄 Text Viewer"),
+		filename:       filename,
+		scroll:         NewScrollView(),
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+	}
+}
+
+// Init initializes the widget
+func (w *TextViewerWidget) Init() tea.Cmd {
+	if w.filename != "" {
+		return w.loadFile()
+	}
+	// Nothing to load
+	w.MarkLoaded()
+	return nil
+}
+
+// Update handles messages
+func (w *TextViewerWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TextMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.err != nil {
+			w.err = msg.err
+		} else {
+			w.content = msg.content
+			w.scroll.SetContent(msg.content)
+			w.MarkUpdated()
+			w.err = nil
+		}
+		if w.updateInterval > 0 {
+			return w, w.schedule.After(w.updateInterval)
+		}
+	case RefreshMsg:
+		// Without a refresh interval the file is only reloaded on request
+		if w.schedule.Due(msg) && w.filename != "" {
+			return w, w.loadFile()
+		}
+	case tea.KeyMsg, tea.MouseMsg:
+		return w, w.scroll.Update(msg)
+	}
+	return w, nil
+}
+
+// KeyBindings implements KeyHelper
+func (w *TextViewerWidget) KeyBindings() []key.Binding {
+	return w.scroll.KeyBindings()
+}
+
+// View renders the widget
+func (w *TextViewerWidget) View() string {
+	var content string
+	if w.filename == "" {
+		content = "No file configured"
+	} else if w.err != nil {
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if w.content == "" {
+		content = "Loading..."
+	} else {
+		content = w.RenderScroll(&w.scroll)
+	}
+	return w.RenderContent(content)
+}
+
+func (w *TextViewerWidget) loadFile() tea.Cmd {
+	id := w.id
+	return func() tea.Msg {
+		data, err := os.ReadFile(w.filename)
+		if err != nil {
+			return TextMsg{id: id, err: err}
+		}
+
+		return TextMsg{id: id, content: string(data)}
+	}
+}
@@ -2,175 +2,203 @@ package widgets
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cj3636/gotui/internal/httpclient"
 )
 
+// wttr.in format strings. Format codes: %l=location, %C=condition,
+// %t=temperature, %w=wind, %p=precipitation, %h=humidity, %m=moon phase,
+// %M=moon day, %S=sunrise, %s=sunset
 const (
-	defaultWeatherLocation = "89701"
-	defaultWeatherUnits    = "u"
-	defaultWeatherView     = "Fq1"
-	defaultMoonLocation    = "moon"
-	defaultMoonView        = "Fq1"
+	weatherFormat = "%l: %C %t\n%w\n%p\n%h"
+	moonFormat    = "%m Day %M\nSunrise: %S\nSunset: %s"
 )
 
-// wttrWidget pulls conditions from wttr.in endpoints.
-type wttrWidget struct {
-	title    string
-	location string
-	units    string
-	view     string
-	summary  string
-	err      error
+// WeatherWidget displays weather information from wttr.in
+type WeatherWidget struct {
+	BaseWidget
+	location       string
+	units          string
+	format         string
+	weatherData    string
+	cacheKey       string
+	err            error
+	updateInterval time.Duration
+	schedule       Schedule
 }
 
-// NewWeatherWidget constructs the weather widget using the WTTR_LOCATION and
-// WTTR_PARAMS environment variables when provided.
-func NewWeatherWidget() Widget {
-	cfg := wttrConfig{
-		location: readLocation("WTTR_LOCATION", defaultWeatherLocation),
-		units:    readUnits("WTTR_UNITS", defaultWeatherUnits),
-		view:     readView("WTTR_VIEW", defaultWeatherView),
-	}
-	return &wttrWidget{title: "Weather", location: cfg.location, units: cfg.units, view: cfg.view}
+// WeatherMsg contains weather data
+type WeatherMsg struct {
+	id   string
+	data string
+	err  error
 }
 
-// NewMoonWidget constructs a moon phase widget that points to the wttr.in/moon
-// endpoint. Location and params can be overridden with WTTR_MOON_LOCATION and
-// WTTR_MOON_PARAMS respectively.
-func NewMoonWidget() Widget {
-	cfg := wttrConfig{
-		location: readLocation("WTTR_MOON_LOCATION", defaultMoonLocation),
-		units:    readUnits("WTTR_MOON_UNITS", defaultWeatherUnits),
-		view:     readView("WTTR_MOON_VIEW", defaultMoonView),
-	}
-	return &wttrWidget{title: "Moon Phase", location: cfg.location, units: cfg.units, view: cfg.view}
+// WidgetID implements Addressed
+func (m WeatherMsg) WidgetID() string { return m.id }
+
+func init() {
+	Register(Type{
+		Name:        "weather",
+		Description: "Current conditions from wttr.in",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewWeatherWidget(
+				spec.ID,
+				spec.Options.String("location", ""),
+				spec.Options.String("units", ""),
+				spec.Refresh,
+			), nil
+		},
+		Options: []Option{
+			{Name: "location", Kind: StringOption, Help: "city, airport code or coordinates (default weather_location)"},
+			{Name: "units", Kind: StringOption, Help: "m (metric), u (US) or M (metric, wind in m/s) (default weather_units)", Check: checkUnits},
+		},
+	})
+	Register(Type{
+		Name:        "moon",
+		Description: "Moon phase, sunrise and sunset from wttr.in",
+		Factory: func(spec Spec) (Widget, error) {
+			return NewMoonWidget(spec.ID, spec.Options.String("location", ""), spec.Refresh), nil
+		},
+		Options: []Option{
+			{Name: "location", Kind: StringOption, Help: "city, airport code or coordinates (default moon_location, then weather_location)"},
+		},
+	})
+}
+
+// NewWeatherWidget creates a new weather widget. units is passed through to
+// wttr.in ("m" metric, "u" US, "M" metric with wind in m/s); leave it empty to
+// let wttr.in pick based on location.
+func NewWeatherWidget(id, location, units string, refreshInterval int) *WeatherWidget {
+	return newWttrWidget(id, "🌤️  Weather", location, units, weatherFormat, refreshInterval)
 }
 
-func (w *wttrWidget) Title() string { return w.title }
+// NewMoonWidget creates a widget showing the moon phase and sun times for a
+// location
+func NewMoonWidget(id, location string, refreshInterval int) *WeatherWidget {
+	return newWttrWidget(id, "🌙 Moon Phase", location, "", moonFormat, refreshInterval)
+}
+
+func newWttrWidget(id, title, location, units, format string, refreshInterval int) *WeatherWidget {
+	w := &WeatherWidget{
+		BaseWidget:     NewBaseWidget(id, title),
+		location:       location,
+		units:          sanitizeUnits(units),
+		format:         format,
+		cacheKey:       cacheKey("wttr", id, location, units, format),
+		updateInterval: time.Duration(refreshInterval) * time.Second,
+		schedule:       NewSchedule(id),
+	}
+	// Show the last known conditions until the first fetch finishes
+	if saved, ok := loadCached(w.cacheKey, &w.weatherData); ok {
+		w.MarkCached(saved)
+	}
+	return w
+}
 
-func (w *wttrWidget) Init() tea.Cmd { return w.fetch() }
+// Init initializes the widget
+func (w *WeatherWidget) Init() tea.Cmd {
+	return w.fetchWeather()
+}
 
-func (w *wttrWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
-	switch m := msg.(type) {
-	case TickMsg:
-		return w, w.fetch()
-	case weatherMsg:
-		w.summary = m.summary
-		w.err = m.err
-		return w, Tick(30 * time.Minute)
+// Update handles messages
+func (w *WeatherWidget) Update(msg tea.Msg) (Widget, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WeatherMsg:
+		w.MarkLoaded()
+		w.ReportError(msg.err)
+		if msg.err != nil {
+			// Keep showing the last data; the footer shows the error
+			if !w.HasData() {
+				w.err = msg.err
+			}
+		} else {
+			w.weatherData = msg.data
+			w.err = nil
+			w.MarkUpdated()
+		}
+		return w, w.schedule.After(w.updateInterval)
+	case RefreshMsg:
+		if !w.schedule.Due(msg) {
+			return w, nil
+		}
+		return w, w.fetchWeather()
 	}
 	return w, nil
 }
 
-func (w *wttrWidget) View(width, _ int) string {
+// View renders the widget
+func (w *WeatherWidget) View() string {
+	content := w.weatherData
 	if w.err != nil {
-		return fmt.Sprintf("Location: %s\nError: %v", w.location, w.err)
+		content = fmt.Sprintf("Error: %v", w.err)
+	} else if content == "" {
+		content = "Loading weather data..."
 	}
-	if w.summary == "" {
-		return fmt.Sprintf("Location: %s\nLoading forecast...", w.location)
-	}
-	return fmt.Sprintf("Location: %s\n%s", w.location, w.summary)
+	return w.RenderContent(content)
 }
 
-type weatherMsg struct {
-	summary string
-	err     error
-}
-
-func (w *wttrWidget) fetch() tea.Cmd {
-	loc := sanitizeLocation(w.location)
-	params := strings.TrimSpace(w.units + w.view)
-
+func (w *WeatherWidget) fetchWeather() tea.Cmd {
+	id, key := w.id, w.cacheKey
 	return func() tea.Msg {
-		client := &http.Client{Timeout: 5 * time.Second}
-		path := fmt.Sprintf("https://wttr.in/%s", loc)
-		if params != "" {
-			path = path + "?" + params
-		}
-		resp, err := client.Get(path)
+		ctx, cancel := httpclient.Context()
+		defer cancel()
+		body, err := httpclient.Fetch(ctx, wttrURL(w.location, w.units, w.format))
 		if err != nil {
-			return weatherMsg{err: err}
+			return WeatherMsg{id: id, err: err}
 		}
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return weatherMsg{err: err}
+
+		data := string(body)
+
+		// Clean up the data
+		lines := strings.Split(data, "\n")
+		var cleaned []string
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				cleaned = append(cleaned, line)
+			}
 		}
-		return weatherMsg{summary: string(body)}
-	}
-}
 
-type wttrConfig struct {
-	location string
-	units    string
-	view     string
-}
+		result := strings.Join(cleaned, "\n")
 
-func readLocation(envKey, fallback string) string {
-	if v := strings.TrimSpace(os.Getenv(envKey)); v != "" {
-		return v
+		saveCached(key, result)
+		return WeatherMsg{id: id, data: result}
 	}
-	return fallback
 }
 
-func sanitizeLocation(loc string) string {
-	loc = strings.TrimSpace(loc)
-	loc = strings.ReplaceAll(loc, " ", "_")
-	if loc == "" {
-		return "_"
+// wttrURL returns the wttr.in URL for a location, units flags and format.
+// wttr.in reads "+" in a location as a space; the format's spaces and
+// newlines are escaped with the rest of the query.
+func wttrURL(location, units, format string) string {
+	location = url.PathEscape(strings.ReplaceAll(location, " ", "+"))
+	query := url.Values{"format": {format}}.Encode()
+	if units != "" {
+		query = units + "&" + query
 	}
-	return loc
+	return fmt.Sprintf("https://wttr.in/%s?%s", location, query)
 }
 
-func readUnits(envKey, fallback string) string {
-	val := strings.TrimSpace(os.Getenv(envKey))
-	if val == "" {
-		val = fallback
-	}
-
-	lower := strings.ToLower(val)
-	base := ""
-	if strings.Contains(lower, "m") {
-		base = "m"
+// checkUnits rejects units settings with letters other than m, u and M
+func checkUnits(units string) error {
+	if strings.Trim(units, "muM") != "" {
+		return fmt.Errorf("%q is not m, u or M", units)
 	}
-	if strings.Contains(lower, "u") {
-		base = "u"
-	}
-	if base == "" {
-		base = string(fallback[0])
-	}
-
-	windMetric := ""
-	if strings.Contains(strings.ToUpper(val), "M") {
-		windMetric = "M"
-	} else if len(fallback) > 1 && strings.Contains(strings.ToUpper(fallback), "M") {
-		windMetric = "M"
-	}
-
-	return base + windMetric
+	return nil
 }
 
-func readView(envKey, fallback string) string {
-	val := strings.TrimSpace(os.Getenv(envKey))
-	if val == "" {
-		val = fallback
-	}
-	allowed := "0123456789AdFnqQ"
-	var builder strings.Builder
-	for _, ch := range val {
-		if strings.ContainsRune(allowed, ch) {
-			builder.WriteRune(ch)
+// sanitizeUnits reduces a units setting to the wttr.in flags it understands:
+// "m" (metric), "u" (US) or "M" (metric, wind in m/s)
+func sanitizeUnits(units string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(units) {
+		if strings.ContainsRune("muM", r) && !strings.ContainsRune(b.String(), r) {
+			b.WriteRune(r)
 		}
 	}
-	cleaned := builder.String()
-	if cleaned == "" {
-		cleaned = fallback
-	}
-	return cleaned
+	return b.String()
 }
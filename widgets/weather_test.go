@@ -0,0 +1,34 @@
+package widgets
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestWttrURL(t *testing.T) {
+	tests := []struct {
+		location, units, format string
+		path, flag              string
+	}{
+		{"New York", "m", weatherFormat, "/New+York", "m"},
+		{"São Paulo", "", moonFormat, "/São+Paulo", ""},
+		{"", "u", weatherFormat, "/", "u"},
+	}
+	for _, tt := range tests {
+		raw := wttrURL(tt.location, tt.units, tt.format)
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Errorf("wttrURL(%q, %q) = %q: %v", tt.location, tt.units, raw, err)
+			continue
+		}
+		if u.Path != tt.path {
+			t.Errorf("wttrURL(%q) path = %q, want %q", tt.location, u.Path, tt.path)
+		}
+		if got := u.Query().Get("format"); got != tt.format {
+			t.Errorf("wttrURL(%q) format = %q, want %q", tt.location, got, tt.format)
+		}
+		if _, ok := u.Query()[tt.flag]; tt.flag != "" && !ok {
+			t.Errorf("wttrURL(%q, %q) = %q lacks the units flag", tt.location, tt.units, raw)
+		}
+	}
+}
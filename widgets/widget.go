@@ -0,0 +1,362 @@
+package widgets
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cj3636/gotui/internal/logging"
+	"github.com/cj3636/gotui/internal/secret"
+	"github.com/cj3636/gotui/internal/theme"
+)
+
+// Widget is the base interface for all widgets
+type Widget interface {
+	// Init initializes the widget
+	Init() tea.Cmd
+
+	// Update handles messages and returns the updated widget
+	Update(msg tea.Msg) (Widget, tea.Cmd)
+
+	// View renders the widget
+	View() string
+
+	// SetSize sets the widget dimensions
+	SetSize(width, height int)
+
+	// Title returns the widget title
+	Title() string
+
+	// ID returns the identifier messages addressed to this widget carry
+	ID() string
+
+	// SetFocused marks the widget as the one receiving key presses
+	SetFocused(focused bool)
+
+	// Loaded reports whether the widget has its first data to show, or has
+	// found out that it can't get any
+	Loaded() bool
+
+	// SetTheme sets the colors and border style the widget is drawn in
+	SetTheme(t theme.Theme)
+
+	// SetStaleAfter sets how long after its last update the widget's data
+	// counts as stale; zero means never
+	SetStaleAfter(d time.Duration)
+}
+
+// KeyHelper is implemented by widgets that handle keys of their own, so the
+// help overlay can list them while the widget has focus
+type KeyHelper interface {
+	KeyBindings() []key.Binding
+}
+
+// BaseWidget provides common functionality for all widgets
+type BaseWidget struct {
+	id      string
+	width   int
+	height  int
+	title   string
+	focused bool
+	loaded  bool
+	theme   theme.Theme
+	style   lipgloss.Style
+	// updated is when the data on show was fetched, and cached whether it
+	// came from the on-disk cache rather than a fetch
+	updated    time.Time
+	cached     bool
+	staleAfter time.Duration
+	// status is a short note of the widget's own for the footer
+	status string
+	// failing records that the last fetch failed, to log the recovery
+	failing bool
+	// fetchErr is the error of the last fetch, shown in the footer while
+	// the panel keeps showing older data
+	fetchErr error
+}
+
+// NewBaseWidget creates a new base widget in the default theme
+func NewBaseWidget(id, title string) BaseWidget {
+	w := BaseWidget{
+		id:    id,
+		title: title,
+		style: lipgloss.NewStyle().Padding(0, 1),
+	}
+	w.SetTheme(theme.Default())
+	return w
+}
+
+// SetTheme sets the colors and border style the widget is drawn in
+func (w *BaseWidget) SetTheme(t theme.Theme) {
+	w.theme = t
+	w.style = w.style.Border(t.Border).BorderForeground(t.BorderColor)
+}
+
+// Theme returns the theme the widget is drawn in, for widgets that style
+// their own content
+func (w *BaseWidget) Theme() theme.Theme {
+	return w.theme
+}
+
+// SetSize sets the widget dimensions
+func (w *BaseWidget) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+}
+
+// Title returns the widget title
+func (w *BaseWidget) Title() string {
+	return w.title
+}
+
+// SetTitle replaces the widget title
+func (w *BaseWidget) SetTitle(title string) {
+	w.title = title
+}
+
+// ID returns the widget identifier
+func (w *BaseWidget) ID() string {
+	return w.id
+}
+
+// SetFocused marks the widget as focused or not
+func (w *BaseWidget) SetFocused(focused bool) {
+	w.focused = focused
+}
+
+// Focused reports whether the widget currently receives key presses
+func (w *BaseWidget) Focused() bool {
+	return w.focused
+}
+
+// Loaded reports whether MarkLoaded has been called
+func (w *BaseWidget) Loaded() bool {
+	return w.loaded
+}
+
+// MarkLoaded records that the widget's first fetch has finished, whether it
+// succeeded or not
+func (w *BaseWidget) MarkLoaded() {
+	w.loaded = true
+}
+
+// MarkCached records that the data on show was loaded from the on-disk
+// cache, saved at the given time. The panel counts as stale until
+// MarkUpdated.
+func (w *BaseWidget) MarkCached(saved time.Time) {
+	w.updated = saved
+	w.cached = true
+}
+
+// MarkUpdated records that the widget has fetched fresh data. The footer
+// shows how long ago that was.
+func (w *BaseWidget) MarkUpdated() {
+	w.updated = time.Now()
+	w.cached = false
+}
+
+// ReportError records the outcome of a fetch. Every failure is logged, as
+// is the first success after one; the log widget shows these with the
+// widget's ID. While the panel has data from an earlier fetch or the cache,
+// the footer shows the error too, so widgets can keep that data on screen
+// instead of replacing it with the error.
+func (w *BaseWidget) ReportError(err error) {
+	if err != nil {
+		slog.Error("refresh failed", logging.WidgetKey, w.id, "err", err)
+	} else if w.failing {
+		slog.Info("refresh succeeded again", logging.WidgetKey, w.id)
+	}
+	w.failing = err != nil
+	w.fetchErr = err
+}
+
+// HasData reports whether the panel shows data from a fetch or the cache
+func (w *BaseWidget) HasData() bool {
+	return !w.updated.IsZero()
+}
+
+// LastUpdated returns when the data on show was fetched, or the zero time
+// for widgets that don't call MarkUpdated
+func (w *BaseWidget) LastUpdated() time.Time {
+	return w.updated
+}
+
+// SetStaleAfter sets how long after its last update the widget's data
+// counts as stale; zero means never
+func (w *BaseWidget) SetStaleAfter(d time.Duration) {
+	w.staleAfter = d
+}
+
+// Stale reports whether the data on show came from the cache or hasn't
+// been updated for longer than the staleness threshold
+func (w *BaseWidget) Stale() bool {
+	return w.cached || w.outdated()
+}
+
+// outdated reports whether the last update is older than the threshold
+func (w *BaseWidget) outdated() bool {
+	return w.staleAfter > 0 && !w.updated.IsZero() && time.Since(w.updated) > w.staleAfter
+}
+
+// SetStatus sets a short note shown in the footer, such as how much of an
+// API quota is left; an empty note removes it
+func (w *BaseWidget) SetStatus(status string) {
+	w.status = status
+}
+
+// footer returns the line shown below the content, if any
+func (w *BaseWidget) footer() string {
+	var parts []string
+	if w.status != "" {
+		parts = append(parts, w.status)
+	}
+	switch {
+	case w.updated.IsZero():
+	case w.cached:
+		parts = append(parts, "cached "+formatAge(time.Since(w.updated)))
+	case w.outdated():
+		parts = append(parts, "⚠ updated "+formatAge(time.Since(w.updated)))
+	default:
+		parts = append(parts, "updated "+formatAge(time.Since(w.updated)))
+	}
+	if w.showsError() {
+		// The URL of a failed request takes up the room the cause needs
+		err := w.fetchErr
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		parts = append(parts, "⚠ "+msg)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// showsError reports whether the last fetch failed while older data is shown
+func (w *BaseWidget) showsError() bool {
+	return w.fetchErr != nil && w.HasData()
+}
+
+// truncate shortens s to width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if lipgloss.Width(b.String()+string(r)) >= width {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "…"
+}
+
+// formatAge describes how long ago something happened, to the nearest unit
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// ContentSize returns the space available to content inside the border,
+// below the title and above the footer
+func (w *BaseWidget) ContentSize() (width, height int) {
+	width = w.width - w.style.GetHorizontalFrameSize()
+	height = w.height - w.style.GetVerticalFrameSize() - 1 // -1 for title
+	if w.footer() != "" {
+		height--
+	}
+
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	return width, height
+}
+
+// RenderScroll renders a scroll view in the content area, with its position
+// indicator in the theme's muted color
+func (w *BaseWidget) RenderScroll(s *ScrollView) string {
+	width, height := w.ContentSize()
+	return s.render(width, height, w.theme.Muted)
+}
+
+// RenderContent renders content with the widget's style and dimensions
+func (w *BaseWidget) RenderContent(content string) string {
+	// Calculate available space
+	availableWidth, availableHeight := w.ContentSize()
+
+	// Error text from APIs can echo a token back
+	content = secret.Redact(content)
+
+	// The title gets exactly one line so the content below keeps its height
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(w.theme.Title).
+		Width(availableWidth).
+		MaxHeight(1).
+		Align(lipgloss.Center)
+
+	title := titleStyle.Render(w.title)
+
+	// Truncate content to fit
+	contentStyle := lipgloss.NewStyle().
+		Width(availableWidth).
+		Height(availableHeight).
+		MaxHeight(availableHeight)
+
+	if w.theme.Text != "" {
+		contentStyle = contentStyle.Foreground(w.theme.Text)
+	}
+
+	renderedContent := contentStyle.Render(content)
+
+	// Combine title, content and footer
+	combined := lipgloss.JoinVertical(lipgloss.Left, title, renderedContent)
+	if footer := w.footer(); footer != "" {
+		// A failed or overdue refresh is worth noticing; cached data is
+		// about to be replaced
+		color := w.theme.Muted
+		if w.showsError() || w.outdated() && !w.cached {
+			color = w.theme.Error
+		}
+		combined = lipgloss.JoinVertical(lipgloss.Left, combined, lipgloss.NewStyle().
+			Foreground(color).
+			Width(availableWidth).
+			MaxHeight(1).
+			Align(lipgloss.Right).
+			Render(truncate(secret.Redact(footer), availableWidth)))
+	}
+
+	// Stale panels get a dimmed border
+	style := w.style
+	if w.focused {
+		style = style.BorderForeground(w.theme.Focus)
+	} else if w.Stale() {
+		style = style.BorderForeground(w.theme.Muted)
+	}
+
+	// Width and Height include padding but not the border, so the panel
+	// comes out exactly width x height
+	return style.
+		Width(w.width - w.style.GetHorizontalBorderSize()).
+		Height(w.height - w.style.GetVerticalBorderSize()).
+		MaxHeight(w.height).
+		Render(combined)
+}
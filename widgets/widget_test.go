@@ -0,0 +1,72 @@
+package widgets
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// useCacheDir points the widget cache at dir for the rest of the test
+func useCacheDir(t *testing.T, dir string) {
+	t.Helper()
+	saved := cacheDir
+	cacheDir = func() string { return dir }
+	t.Cleanup(func() { cacheDir = saved })
+}
+
+func TestFailedFetchKeepsData(t *testing.T) {
+	useCacheDir(t, t.TempDir())
+	lipgloss.SetColorProfile(termenv.Ascii)
+	offline := &url.Error{Op: "Get", URL: "https://ipinfo.io/json", Err: errors.New("no such host")}
+
+	w := NewIPWidget("ip", 60)
+	w.SetSize(60, 10)
+	w.ipInfo.IP = "192.0.2.7"
+	w.MarkCached(time.Now().Add(-5 * time.Minute))
+
+	w.Update(IPMsg{id: "ip", err: offline})
+	view := w.View()
+	if !strings.Contains(view, "192.0.2.7") {
+		t.Errorf("cached data replaced by the error:\n%s", view)
+	}
+	if !strings.Contains(view, "cached 5m ago • ⚠ no such host") {
+		t.Errorf("footer doesn't show the error:\n%s", view)
+	}
+
+	w.Update(IPMsg{id: "ip", info: IPInfo{IP: "192.0.2.8"}})
+	view = w.View()
+	if !strings.Contains(view, "192.0.2.8") || strings.Contains(view, "⚠") {
+		t.Errorf("a successful fetch should clear the error:\n%s", view)
+	}
+
+	// Without data to keep, the error takes the panel
+	empty := NewIPWidget("ip-2", 60)
+	empty.SetSize(60, 10)
+	empty.Update(IPMsg{id: "ip-2", err: offline})
+	if view := empty.View(); !strings.Contains(view, "Error:") {
+		t.Errorf("error not shown on an empty panel:\n%s", view)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a bit too long", 10, "a bit too…"},
+		{"⚠ warning", 4, "⚠ w…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}